@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/rewrite"
+)
+
+// ----------------------------------------------------------------------
+// エンジン接続のフラグ (全コマンド共通)
+// ----------------------------------------------------------------------
+
+// engineFlags は Executor の初期化に使用するフラグです。
+type engineFlags struct {
+	apiURL         string
+	httpTimeout    time.Duration
+	readyTimeout   time.Duration
+	allSpeakers    bool
+	aliasFile      string
+	snapshotFile   string
+	maxParallel    int
+	segmentTimeout time.Duration
+	rateLimit      time.Duration
+	maxAttempts    int
+	logLevel       string
+}
+
+// register はフラグを fs に登録します。
+func (f *engineFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.apiURL, "url", "", "VOICEVOXエンジンのURL (省略時は環境変数 VOICEVOX_API_URL)")
+	fs.DurationVar(&f.httpTimeout, "http-timeout", appClientTimeout, "APIリクエストのタイムアウト")
+	fs.DurationVar(&f.readyTimeout, "ready-timeout", 0, "エンジンが応答可能になるまで待機する期限 (0 は待機しない)")
+	fs.BoolVar(&f.allSpeakers, "all-speakers", false, "エンジンが提供するすべての話者・スタイルを登録する")
+	fs.StringVar(&f.aliasFile, "aliases", "", "話者の別名設定ファイル (YAML/JSON)")
+	fs.StringVar(&f.snapshotFile, "snapshot", "", "話者データのスナップショットファイル")
+	fs.IntVar(&f.maxParallel, "parallel", voicevox.DefaultMaxParallelSegments, "セグメントの最大並列数")
+	fs.DurationVar(&f.segmentTimeout, "segment-timeout", voicevox.DefaultSegmentTimeout, "セグメントごとのタイムアウト")
+	fs.DurationVar(&f.rateLimit, "rate-limit", voicevox.DefaultSegmentRateLimit, "APIリクエストの最小間隔")
+	fs.IntVar(&f.maxAttempts, "max-attempts", 1, "セグメントごとの合成の最大試行回数 (1 は再試行しない)")
+	fs.StringVar(&f.logLevel, "log-level", "info", "ログレベル (debug, info, warn, error)")
+}
+
+// factoryOptions はフラグの値を FactoryOption に変換します。
+func (f *engineFlags) factoryOptions() []voicevox.FactoryOption {
+	opts := []voicevox.FactoryOption{
+		voicevox.WithEngineConfig(voicevox.EngineConfig{
+			MaxParallelSegments: f.maxParallel,
+			SegmentTimeout:      f.segmentTimeout,
+			SegmentRateLimit:    f.rateLimit,
+			Retry:               voicevox.RetryPolicy{MaxAttempts: f.maxAttempts},
+		}),
+	}
+	if f.apiURL != "" {
+		opts = append(opts, voicevox.WithAPIURL(f.apiURL))
+	}
+	if f.readyTimeout > 0 {
+		opts = append(opts, voicevox.WithReadyTimeout(f.readyTimeout))
+	}
+	if f.allSpeakers {
+		opts = append(opts, voicevox.WithAllSpeakers())
+	}
+	if f.aliasFile != "" {
+		opts = append(opts, voicevox.WithSpeakerAliasFile(f.aliasFile))
+	}
+	if f.snapshotFile != "" {
+		opts = append(opts, voicevox.WithSpeakerSnapshot(f.snapshotFile))
+	}
+	return opts
+}
+
+// newExecutor はログレベルを設定し、フラグの内容で Executor を初期化します。
+func (f *engineFlags) newExecutor(ctx context.Context, extra ...voicevox.FactoryOption) (voicevox.EngineExecutor, error) {
+	if err := setupLogger(f.logLevel); err != nil {
+		return nil, err
+	}
+	opts := append(f.factoryOptions(), extra...)
+	return voicevox.NewEngineExecutor(ctx, f.httpTimeout, true, opts...)
+}
+
+// ----------------------------------------------------------------------
+// スクリプト処理のフラグ (synth / lint / plan 共通)
+// ----------------------------------------------------------------------
+
+// scriptFlags はスクリプトの解釈に影響するフラグです。
+type scriptFlags struct {
+	fallbackTag string
+	rulesFile   string
+	normalize   bool
+	autoCorrect bool
+}
+
+// register はフラグを fs に登録します。
+func (f *scriptFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.fallbackTag, "fallback", "", "タグのないスクリプトに使用する話者・スタイルタグ (例: \"[ずんだもん][ノーマル]\")")
+	fs.StringVar(&f.rulesFile, "rules", "", "読み上げ用テキストの置換ルールファイル (YAML/JSON)")
+	fs.BoolVar(&f.normalize, "normalize", false, "数字・日付・単位・英字略語などを読み上げ用の表記に正規化する")
+	fs.BoolVar(&f.autoCorrect, "autocorrect", false, "未定義のタグを最も近い登録済みのタグに自動修正する")
+}
+
+// executeOptions はフラグの値を ExecuteOption に変換します。
+func (f *scriptFlags) executeOptions() ([]voicevox.ExecuteOption, error) {
+	opts := []voicevox.ExecuteOption{voicevox.WithFallbackTag(f.fallbackTag)}
+	if f.rulesFile != "" {
+		rules, err := rewrite.LoadFile(f.rulesFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, voicevox.WithTextRules(rules))
+	}
+	if f.normalize {
+		opts = append(opts, voicevox.WithNormalization())
+	}
+	if f.autoCorrect {
+		opts = append(opts, voicevox.WithTagAutoCorrect())
+	}
+	return opts, nil
+}
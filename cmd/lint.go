@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+)
+
+// ----------------------------------------------------------------------
+// lint コマンド
+// ----------------------------------------------------------------------
+
+// runLint はスクリプトを解析し、未定義のタグや前処理後に空になるテキストなどの問題を報告します。
+// 問題が見つかった場合は ErrInvalidScript を返します (終了コード exitParse)。
+func runLint(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	var engine engineFlags
+	var script scriptFlags
+	engine.register(fs)
+	script.register(fs)
+
+	infos, err := planScript(ctx, fs, args, &engine, &script)
+
+	var batchErr *voicevox.ErrSynthesisBatch
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
+	}
+
+	if batchErr != nil {
+		for _, segErr := range batchErr.Errors {
+			fmt.Printf("セグメント %d %s %q: %v\n", segErr.Index, segErr.SpeakerTag, segErr.Text, segErr.Err)
+		}
+		return &voicevox.ErrInvalidScript{Details: fmt.Sprintf("%d 件の問題が見つかりました", batchErr.TotalErrors)}
+	}
+
+	fmt.Printf("問題は見つかりませんでした (%d セグメント)\n", len(infos))
+	return nil
+}
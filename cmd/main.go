@@ -2,13 +2,18 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
 )
 
 // ----------------------------------------------------------------------
@@ -16,68 +21,215 @@ import (
 // ----------------------------------------------------------------------
 
 const (
-	// アプリケーション全体のHTTPクライアントタイムアウト
+	// アプリケーション全体のHTTPクライアントタイムアウト (-http-timeout の既定値)
 	appClientTimeout = 60 * time.Second
 
-	// 出力ファイル名
+	// 出力ファイル名 (-o の既定値)
 	outputFilename = "asset/tts_output.wav"
 )
 
+// 終了コード
+// 呼び出し元のスクリプトやCIが、失敗の原因を区別できるようにします。
+const (
+	exitOK         = 0 // 正常終了
+	exitFailure    = 1 // 音声合成の失敗、ファイルの入出力エラーなど
+	exitUsage      = 2 // コマンドやフラグの指定誤り
+	exitParse      = 3 // スクリプトの解析エラー、未定義のタグ、エンジンが入力を受け付けなかった (スクリプトの修正が必要)
+	exitConnection = 4 // VOICEVOXエンジンへの接続失敗、エンジンの障害 (5xx)
+	exitPartial    = 5 // 一部のセグメントをスキップして出力した (-partial 指定時)
+)
+
 // ----------------------------------------------------------------------
-// 入力スクリプト
+// サブコマンド
 // ----------------------------------------------------------------------
 
-const inputScript = `
-[ずんだもん][ノーマル] [呼びかけ] こんにちは、ずんだもんです。
-[めたん][ツンツン] テスト用のスクリプトを開始します。
+// command はサブコマンドの定義です。
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
 
-// タグのない行（前のセグメントに結合されることを期待）
-これはタグなし行です。前のセグメントに結合されます。
+// commands は利用可能なサブコマンドの一覧です。
+var commands = []command{
+	{name: "synth", summary: "スクリプトを音声合成してWAVファイル (と字幕) を出力します", run: runSynth},
+	{name: "speakers", summary: "登録されている話者・スタイルタグと Style ID を一覧表示します", run: runSpeakers},
+	{name: "lint", summary: "スクリプトを解析し、未定義のタグや前処理の問題を報告します", run: runLint},
+	{name: "plan", summary: "APIを呼び出さずに、セグメントの分割結果と推定タイムラインを表示します", run: runPlan},
+	{name: "serve", summary: "エンジンを REST API のジョブサービスとして公開します", run: runServe},
+}
 
-[ずんだもん][あまあま] まず、短い文章の合成を確認するのだ。
+// usageError はコマンドやフラグの指定誤りを示します。
+// reported が true の場合、エラーは flag パッケージによって出力済みです。
+type usageError struct {
+	err      error
+	reported bool
+}
 
-// 長文による強制分割のテスト（200文字の制限を確認）
-[めたん][ノーマル] これは、文字数制限によるセグメントの強制分割をテストするための非常に長い文章であり、その長さは200文字の制限を大きく超えています。パーサーは、この文章を自然な句読点の位置で分割することを試みますが、それが見つからない場合は、200文字の制限内で機械的に強制的にセグメントを分割するべきです。このテストにより、パーサーがAPIリクエストの安全性を保証し、VOICEVOXエンジンへの過負荷を防ぐことを確認します。（この行は220文字以上あることを想定し、最低2セグメントに強制分割されることを期待）。
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
 
-// 複数行にわたる同じタグのテスト（一行一セグメントの強制を確認）
-[ずんだもん][ノーマル] これは複数行にわたるテストです。
-[ずんだもん][ノーマル] 同じタグが連続しても、行ごとにセグメントが分割されることを確認します。
-[ずんだもん][ノーマル] この挙動が意図通りであることを検証します。
-` // 200文字以上の長文を想定
+func (e *usageError) Unwrap() error {
+	return e.err
+}
 
 func main() {
-	// ログ設定
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: slog.LevelInfo,
-	})))
+	os.Exit(run(os.Args[1:]))
+}
 
-	// 実行コンテキスト
-	ctx := context.Background()
+// run はサブコマンドを実行し、終了コードを返します。
+func run(args []string) int {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return exitUsage
+	}
 
-	slog.Info("VOICEVOX Executorの初期化を開始します...")
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		printUsage(os.Stdout)
+		return exitOK
+	}
 
-	// 1. Executorの初期化 (voicevoxパッケージに集約されたロジックを使用)
-	//    voicevoxOutput: true (実行するため)
-	//    appClientTimeout: 接続/ロードのタイムアウトとして使用
-	voicevoxExecutor, err := voicevox.NewEngineExecutor(ctx, appClientTimeout, true)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+
+		// Ctrl+C や SIGTERM で実行中の合成処理をキャンセルする
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		err := cmd.run(ctx, args[1:])
+		code := exitCode(err)
+		var usageErr *usageError
+		switch {
+		case errors.As(err, &usageErr):
+			if !usageErr.reported {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", name, usageErr.err)
+			}
+		case code != exitOK:
+			slog.Error("コマンドの実行に失敗しました。", "command", name, "exit_code", code, "error", err)
+		}
+		return code
+	}
 
-	// voicevoxOutput が true なので、voicevoxExecutor は nil でないはず
-	if err != nil {
-		slog.Error("VOICEVOX Executorの初期化に失敗しました。", "error", err)
-		slog.Error("VOICEVOXエンジンが起動しているか、またはAPI URLが正しいか確認してください。")
-		os.Exit(1)
+	fmt.Fprintf(os.Stderr, "不明なコマンドです: %s\n\n", name)
+	printUsage(os.Stderr)
+	return exitUsage
+}
+
+// printUsage はコマンド全体の使い方を出力します。
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "使い方: go-voicevox <command> [flags] [script-file]")
+	fmt.Fprintln(w, "       go-voicevox serve [flags]")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "script-file を省略するか \"-\" を指定した場合は、標準入力からスクリプトを読み込みます。")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "コマンド:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
 	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "各コマンドのフラグは go-voicevox <command> -h で確認できます。")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "終了コード:")
+	fmt.Fprintf(w, "  %d  正常終了\n", exitOK)
+	fmt.Fprintf(w, "  %d  音声合成の失敗、ファイルの入出力エラーなど\n", exitFailure)
+	fmt.Fprintf(w, "  %d  コマンドやフラグの指定誤り\n", exitUsage)
+	fmt.Fprintf(w, "  %d  スクリプトの解析エラー、未定義のタグ、エンジンが入力を受け付けなかった\n", exitParse)
+	fmt.Fprintf(w, "  %d  VOICEVOXエンジンへの接続失敗、エンジンの障害\n", exitConnection)
+	fmt.Fprintf(w, "  %d  一部のセグメントをスキップして出力した\n", exitPartial)
+}
 
-	// 2. 音声合成の実行
-	slog.Info("音声合成処理を開始します。", "output", outputFilename)
+// exitCode はエラーの種類から終了コードを決定します。
+func exitCode(err error) int {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
 
-	// Executeを実行
-	err = voicevoxExecutor.Execute(ctx, inputScript, outputFilename)
-	if err != nil {
-		slog.Error("音声合成の実行に失敗しました。", "error", err)
-		os.Exit(1)
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return exitUsage
+	}
+
+	// 部分出力はバッチエラーをラップしているため、個々の原因より先に判定する
+	var partialErr *voicevox.ErrPartialSynthesis
+	if errors.As(err, &partialErr) {
+		return exitPartial
 	}
 
-	absPath, _ := filepath.Abs(outputFilename)
-	slog.Info(fmt.Sprintf("✅ 音声合成が正常に完了しました。ファイル: %s", absPath))
+	var networkErr *api.ErrAPINetwork
+	var notReadyErr *api.ErrEngineNotReady
+	var respErr *api.ErrAPIResponse
+	if errors.As(err, &networkErr) || errors.As(err, &notReadyErr) {
+		return exitConnection
+	}
+	if errors.As(err, &respErr) && respErr.IsServerError() {
+		return exitConnection
+	}
+
+	// エンジンがテキストやカナを受け付けなかった場合もスクリプトの修正が必要
+	var scriptErr *voicevox.ErrInvalidScript
+	var tagErr *voicevox.ErrUnknownStyleTag
+	if errors.As(err, &scriptErr) || errors.As(err, &tagErr) {
+		return exitParse
+	}
+	if respErr != nil && respErr.IsInputError() {
+		return exitParse
+	}
+
+	return exitFailure
+}
+
+// ----------------------------------------------------------------------
+// 共通処理
+// ----------------------------------------------------------------------
+
+// setupLogger はログレベル ("debug", "info", "warn", "error") を設定します。
+// 標準出力は一覧表示などのコマンド出力に使用するため、ログは標準エラー出力に書き出します。
+func setupLogger(level string) error {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return &usageError{err: fmt.Errorf("不正なログレベルです: %s", level)}
+	}
+
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: logLevel,
+	})))
+	return nil
+}
+
+// parseFlags はフラグを解析し、残りの引数からスクリプトファイルのパスを返します。
+func parseFlags(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", err
+		}
+		return "", &usageError{err: err, reported: true}
+	}
+
+	switch fs.NArg() {
+	case 0:
+		return "-", nil
+	case 1:
+		return fs.Arg(0), nil
+	default:
+		return "", &usageError{err: fmt.Errorf("スクリプトファイルは1つだけ指定してください: %v", fs.Args())}
+	}
+}
+
+// readScript はファイルまたは標準入力 (path が "-" の場合) からスクリプトを読み込みます。
+func readScript(path string) (string, error) {
+	var content []byte
+	var err error
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("スクリプトの読み込みに失敗しました (%s): %w", path, err)
+	}
+	return string(content), nil
 }
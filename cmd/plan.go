@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+)
+
+// ----------------------------------------------------------------------
+// plan コマンド
+// ----------------------------------------------------------------------
+
+// runPlan はAPIを呼び出さずに、セグメントの分割結果・Style ID・推定タイムラインを表示します。
+func runPlan(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	var engine engineFlags
+	var script scriptFlags
+	engine.register(fs)
+	script.register(fs)
+
+	infos, err := planScript(ctx, fs, args, &engine, &script)
+	var batchErr *voicevox.ErrSynthesisBatch
+	if err != nil && !errors.As(err, &batchErr) {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tSTART\tEND\tSTYLE_ID\tTAG\tTEXT")
+	for _, info := range infos {
+		switch {
+		case info.Kind == parser.SegmentSilence:
+			fmt.Fprintf(w, "%d\t%s\t%s\t-\t-\t(無音)\n", info.Index, info.Start, info.End)
+		case info.Skipped:
+			fmt.Fprintf(w, "%d\t%s\t%s\t?\t%s\t%s\n", info.Index, info.Start, info.End, info.SpeakerTag, info.Text)
+		default:
+			fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", info.Index, info.Start, info.End, info.StyleID, info.SpeakerTag, info.SpeechText)
+		}
+	}
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+
+	if batchErr != nil {
+		return &voicevox.ErrInvalidScript{Details: "合成できないセグメントがあります", WrappedErr: batchErr}
+	}
+	return nil
+}
+
+// planScript はフラグを解析してスクリプトを読み込み、Executor の Plan を実行します。
+// lint / plan コマンドの共通処理です。
+func planScript(ctx context.Context, fs *flag.FlagSet, args []string, engine *engineFlags, script *scriptFlags) ([]voicevox.SegmentInfo, error) {
+	scriptPath, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	scriptContent, err := readScript(scriptPath)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := script.executeOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	executor, err := engine.newExecutor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	planner, ok := executor.(voicevox.Planner)
+	if !ok {
+		return nil, errors.New("この Executor はスクリプトの事前確認に対応していません")
+	}
+	return planner.Plan(ctx, scriptContent, opts...)
+}
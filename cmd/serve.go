@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/server"
+)
+
+// serverShutdownTimeout は終了シグナルを受け取ってから、処理中のリクエストの完了を待つ時間です。
+const serverShutdownTimeout = 10 * time.Second
+
+// ----------------------------------------------------------------------
+// serve コマンド
+// ----------------------------------------------------------------------
+
+// runServe はエンジンを REST API のジョブサービスとして公開します。
+// 終了シグナルを受け取ると、実行中のジョブをキャンセルしてサーバーを停止します。
+func runServe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var engine engineFlags
+	var script scriptFlags
+	engine.register(fs)
+	script.register(fs)
+	addr := fs.String("addr", ":8080", "待ち受けるアドレス")
+	workers := fs.Int("workers", server.DefaultWorkers, "同時に実行するジョブの数")
+	queueSize := fs.Int("queue-size", server.DefaultQueueSize, "実行待ちにできるジョブの最大数")
+	retention := fs.Duration("job-retention", server.DefaultJobRetention, "完了したジョブと合成結果を保持する期間")
+	refreshInterval := fs.Duration("refresh-interval", 0, "話者データをエンジンから再取得する間隔 (0 は再取得しない)")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{err: err, reported: true}
+	}
+	if fs.NArg() > 0 {
+		return &usageError{err: fmt.Errorf("serve コマンドは引数を取りません: %v", fs.Args())}
+	}
+
+	opts, err := script.executeOptions()
+	if err != nil {
+		return err
+	}
+
+	var factoryOpts []voicevox.FactoryOption
+	if *refreshInterval > 0 {
+		factoryOpts = append(factoryOpts, voicevox.WithRefreshInterval(*refreshInterval))
+	}
+	executor, err := engine.newExecutor(ctx, factoryOpts...)
+	if err != nil {
+		return err
+	}
+	// 話者データのバックグラウンドでの再取得を停止する
+	if closer, ok := executor.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	synthesizer, ok := executor.(voicevox.Synthesizer)
+	if !ok {
+		return errors.New("この Executor はメモリ上での合成に対応していません")
+	}
+
+	jobServer := server.New(synthesizer,
+		server.WithWorkers(*workers),
+		server.WithQueueSize(*queueSize),
+		server.WithJobRetention(*retention),
+		server.WithExecuteOptions(opts...))
+	jobServer.Start(ctx)
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           jobServer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("HTTPサーバーを起動しました。", "addr", *addr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("HTTPサーバーが停止しました: %w", err)
+	case <-ctx.Done():
+	}
+
+	slog.Info("終了シグナルを受け取りました。HTTPサーバーを停止します。")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serverShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("HTTPサーバーの停止に失敗しました: %w", err)
+	}
+	return nil
+}
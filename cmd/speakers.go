@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+)
+
+// ----------------------------------------------------------------------
+// speakers コマンド
+// ----------------------------------------------------------------------
+
+// runSpeakers は登録されている話者・スタイルタグと Style ID を Style ID 順に一覧表示します。
+func runSpeakers(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("speakers", flag.ContinueOnError)
+	var engine engineFlags
+	engine.register(fs)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{err: err, reported: true}
+	}
+	if fs.NArg() > 0 {
+		return &usageError{err: fmt.Errorf("speakers コマンドは引数を取りません: %v", fs.Args())}
+	}
+
+	executor, err := engine.newExecutor(ctx)
+	if err != nil {
+		return err
+	}
+	lister, ok := executor.(voicevox.StyleLister)
+	if !ok {
+		return fmt.Errorf("この Executor は話者の一覧表示に対応していません")
+	}
+
+	styles := lister.Styles()
+	tags := make([]string, 0, len(styles))
+	for tag := range styles {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if styles[tags[i]] != styles[tags[j]] {
+			return styles[tags[i]] < styles[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "STYLE_ID\tTAG")
+	for _, tag := range tags {
+		fmt.Fprintf(w, "%d\t%s\n", styles[tag], tag)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/cache"
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
+)
+
+// ----------------------------------------------------------------------
+// synth コマンド
+// ----------------------------------------------------------------------
+
+// runSynth はスクリプトを音声合成し、WAVファイル (と字幕ファイル) を出力します。
+func runSynth(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("synth", flag.ContinueOnError)
+	var engine engineFlags
+	var script scriptFlags
+	engine.register(fs)
+	script.register(fs)
+	output := fs.String("o", outputFilename, "出力するWAVファイルのパス")
+	dictFile := fs.String("dict", "", "起動時にエンジンのユーザー辞書と同期する辞書ファイル (YAML/JSON/CSV)")
+	dictPrune := fs.Bool("dict-prune", false, "辞書ファイルにない単語をエンジンのユーザー辞書から削除する")
+	partial := fs.Bool("partial", false, "一部のセグメントが失敗しても成功したセグメントで出力する")
+	fillSilence := fs.Bool("fill-silence", false, "-partial 指定時に、失敗したセグメントを推定長の無音で置き換える")
+	subtitles := fs.String("subtitles", "", "WAVと並べて出力する字幕の形式 (カンマ区切り: srt,vtt)")
+	sampleRate := fs.Uint("sample-rate", 0, "出力WAVのサンプリングレート (0 は最初のセグメントに合わせる)")
+	channels := fs.Uint("channels", 0, "出力WAVのチャンネル数 (0 は最初のセグメントに合わせる)")
+	cacheDir := fs.String("cache-dir", "", "合成済みセグメントのキャッシュディレクトリ")
+
+	scriptPath, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	formats, err := parseSubtitleFormats(*subtitles)
+	if err != nil {
+		return err
+	}
+
+	scriptContent, err := readScript(scriptPath)
+	if err != nil {
+		return err
+	}
+	opts, err := script.executeOptions()
+	if err != nil {
+		return err
+	}
+	if *partial {
+		opts = append(opts, voicevox.WithPartialOutput(*fillSilence))
+	}
+	if len(formats) > 0 {
+		opts = append(opts, voicevox.WithSubtitles(formats...))
+	}
+	if *sampleRate > 0 || *channels > 0 {
+		opts = append(opts, voicevox.WithOutputFormat(uint32(*sampleRate), uint16(*channels)))
+	}
+	if *cacheDir != "" {
+		segmentCache, err := cache.NewFileCache(*cacheDir)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, voicevox.WithSegmentCache(segmentCache))
+	}
+
+	var factoryOpts []voicevox.FactoryOption
+	if *dictFile != "" {
+		factoryOpts = append(factoryOpts, voicevox.WithDictionaryFile(*dictFile, *dictPrune))
+	}
+	executor, err := engine.newExecutor(ctx, factoryOpts...)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("音声合成処理を開始します。", "output", *output)
+	err = executor.Execute(ctx, scriptContent, *output, opts...)
+	var partialErr *voicevox.ErrPartialSynthesis
+	if err != nil && !errors.As(err, &partialErr) {
+		return err
+	}
+
+	absPath, _ := filepath.Abs(*output)
+	if partialErr != nil {
+		slog.Warn("一部のセグメントをスキップして音声を出力しました。", "file", absPath, "skipped_indices", partialErr.SkippedIndices)
+		return err
+	}
+	slog.Info(fmt.Sprintf("✅ 音声合成が正常に完了しました。ファイル: %s", absPath))
+	return nil
+}
+
+// parseSubtitleFormats はカンマ区切りの字幕形式 (例: "srt,vtt") を解析します。
+func parseSubtitleFormats(value string) ([]subtitle.Format, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var formats []subtitle.Format
+	for _, name := range strings.Split(value, ",") {
+		format := subtitle.Format(strings.ToLower(strings.TrimSpace(name)))
+		switch format {
+		case subtitle.FormatSRT, subtitle.FormatWebVTT:
+			formats = append(formats, format)
+		default:
+			return nil, &usageError{err: fmt.Errorf("未対応の字幕形式です: %s (srt, vtt のいずれかを指定してください)", name)}
+		}
+	}
+	return formats, nil
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/shouni/go-http-kit v1.1.2
 	golang.org/x/time v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// AudioQuery のデコード/エンコード
+// ----------------------------------------------------------------------
+
+// DecodeAudioQuery は /audio_query の応答JSONを AudioQuery にデコードします。
+func DecodeAudioQuery(data []byte) (*AudioQuery, error) {
+	var q AudioQuery
+	if err := json.Unmarshal(data, &q); err != nil {
+		return nil, &ErrInvalidJSON{Details: "AudioQueryのデコード", WrappedErr: err}
+	}
+	return &q, nil
+}
+
+// Encode は AudioQuery を /synthesis に渡せるJSONへエンコードします。
+func (q *AudioQuery) Encode() ([]byte, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, &ErrInvalidJSON{Details: "AudioQueryのエンコード", WrappedErr: err}
+	}
+	return data, nil
+}
+
+// Clone は AudioQuery のディープコピーを返します。
+func (q *AudioQuery) Clone() *AudioQuery {
+	clone := *q
+	clone.Kana = clonePtr(q.Kana)
+	clone.Extra = cloneExtra(q.Extra)
+	if q.AccentPhrases == nil {
+		return &clone
+	}
+	clone.AccentPhrases = make([]AccentPhrase, len(q.AccentPhrases))
+	for i, phrase := range q.AccentPhrases {
+		phrase.Moras = make([]Mora, len(q.AccentPhrases[i].Moras))
+		for j, mora := range q.AccentPhrases[i].Moras {
+			phrase.Moras[j] = mora.clone()
+		}
+		if phrase.PauseMora != nil {
+			pause := phrase.PauseMora.clone()
+			phrase.PauseMora = &pause
+		}
+		phrase.Extra = cloneExtra(phrase.Extra)
+		clone.AccentPhrases[i] = phrase
+	}
+	return &clone
+}
+
+func (m Mora) clone() Mora {
+	m.Consonant = clonePtr(m.Consonant)
+	m.ConsonantLength = clonePtr(m.ConsonantLength)
+	m.Extra = cloneExtra(m.Extra)
+	return m
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneExtra(extra map[string]json.RawMessage) map[string]json.RawMessage {
+	if extra == nil {
+		return nil
+	}
+	clone := make(map[string]json.RawMessage, len(extra))
+	for name, value := range extra {
+		clone[name] = append(json.RawMessage(nil), value...)
+	}
+	return clone
+}
+
+// ----------------------------------------------------------------------
+// AudioQuery の編集ヘルパー
+// ----------------------------------------------------------------------
+
+// EachMora はすべてのアクセント句のモーラ（ポーズモーラを含む）に対して fn を呼び出します。
+// fn にはモーラへのポインタが渡されるため、長さやピッチをその場で変更できます。
+func (q *AudioQuery) EachMora(fn func(phraseIndex int, mora *Mora)) {
+	for i := range q.AccentPhrases {
+		phrase := &q.AccentPhrases[i]
+		for j := range phrase.Moras {
+			fn(i, &phrase.Moras[j])
+		}
+		if phrase.PauseMora != nil {
+			fn(i, phrase.PauseMora)
+		}
+	}
+}
+
+// Duration はクエリから合成される音声のおおよその長さを算出します。
+// 前後の無音、各モーラの子音・母音長の合計を SpeedScale で割った値です。
+func (q *AudioQuery) Duration() time.Duration {
+	seconds := 0.0
+	q.EachMora(func(_ int, m *Mora) {
+		if m.ConsonantLength != nil {
+			seconds += *m.ConsonantLength
+		}
+		seconds += m.VowelLength
+	})
+	if q.SpeedScale > 0 {
+		seconds /= q.SpeedScale
+	}
+	seconds += q.PrePhonemeLength + q.PostPhonemeLength
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// ----------------------------------------------------------------------
+// 未知フィールドを保持するJSON変換
+// ----------------------------------------------------------------------
+
+func (q *AudioQuery) UnmarshalJSON(data []byte) error {
+	type plain AudioQuery
+	extra, err := unmarshalWithExtra(data, (*plain)(q))
+	if err != nil {
+		return err
+	}
+	q.Extra = extra
+	return nil
+}
+
+func (q AudioQuery) MarshalJSON() ([]byte, error) {
+	type plain AudioQuery
+	return marshalWithExtra(plain(q), q.Extra)
+}
+
+func (p *AccentPhrase) UnmarshalJSON(data []byte) error {
+	type plain AccentPhrase
+	extra, err := unmarshalWithExtra(data, (*plain)(p))
+	if err != nil {
+		return err
+	}
+	p.Extra = extra
+	return nil
+}
+
+func (p AccentPhrase) MarshalJSON() ([]byte, error) {
+	type plain AccentPhrase
+	return marshalWithExtra(plain(p), p.Extra)
+}
+
+func (m *Mora) UnmarshalJSON(data []byte) error {
+	type plain Mora
+	extra, err := unmarshalWithExtra(data, (*plain)(m))
+	if err != nil {
+		return err
+	}
+	m.Extra = extra
+	return nil
+}
+
+func (m Mora) MarshalJSON() ([]byte, error) {
+	type plain Mora
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// unmarshalWithExtra は data を v にデコードし、v の構造体に定義されていないフィールドを返します。
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for _, name := range jsonFieldNames(reflect.TypeOf(v).Elem()) {
+		delete(raw, name)
+	}
+
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	return raw, nil
+}
+
+// marshalWithExtra は v をエンコードし、extra のフィールドを追加します。
+// 既知のフィールドと名前が衝突する場合は v の値が優先されます。
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, known := raw[name]; !known {
+			raw[name] = value
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// jsonFieldNames は構造体型のJSONフィールド名の一覧を返します。
+func jsonFieldNames(t reflect.Type) []string {
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// ----------------------------------------------------------------------
+// Prosody (話速・音高・抑揚・音量の上書き)
+// ----------------------------------------------------------------------
+
+// Float は Prosody のフィールドに値を設定するためのヘルパーです。
+// 例: api.Prosody{SpeedScale: api.Float(1.2)}
+func Float(v float64) *float64 {
+	return &v
+}
+
+// IsZero は上書き対象のフィールドが一つも設定されていない場合に true を返します。
+func (p Prosody) IsZero() bool {
+	return p.SpeedScale == nil && p.PitchScale == nil && p.IntonationScale == nil && p.VolumeScale == nil
+}
+
+// Merge は p に override を重ねた結果を返します。override で設定されたフィールドが優先されます。
+func (p Prosody) Merge(override Prosody) Prosody {
+	if override.SpeedScale != nil {
+		p.SpeedScale = override.SpeedScale
+	}
+	if override.PitchScale != nil {
+		p.PitchScale = override.PitchScale
+	}
+	if override.IntonationScale != nil {
+		p.IntonationScale = override.IntonationScale
+	}
+	if override.VolumeScale != nil {
+		p.VolumeScale = override.VolumeScale
+	}
+	return p
+}
+
+// ApplyProsody は Prosody で設定されたフィールドをクエリに反映します。
+func (q *AudioQuery) ApplyProsody(p Prosody) {
+	if p.SpeedScale != nil {
+		q.SpeedScale = *p.SpeedScale
+	}
+	if p.PitchScale != nil {
+		q.PitchScale = *p.PitchScale
+	}
+	if p.IntonationScale != nil {
+		q.IntonationScale = *p.IntonationScale
+	}
+	if p.VolumeScale != nil {
+		q.VolumeScale = *p.VolumeScale
+	}
+}
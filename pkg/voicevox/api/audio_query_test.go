@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestAudioQueryRoundTrip は構造体に定義されていないフィールドが Extra に保持され、
+// DecodeAudioQuery → Encode の往復で失われないことを確認します。
+func TestAudioQueryRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantExtra []string // AudioQuery.Extra に保持されるべきフィールド名
+	}{
+		{
+			name:  "未知のフィールドなし",
+			query: `{"accent_phrases":[],"speedScale":1,"pitchScale":0,"intonationScale":1,"volumeScale":1,"prePhonemeLength":0.1,"postPhonemeLength":0.1,"outputSamplingRate":24000,"outputStereo":false}`,
+		},
+		{
+			name:      "トップレベルの未知のフィールド",
+			query:     `{"accent_phrases":[],"speedScale":1,"pitchScale":0,"intonationScale":1,"volumeScale":1,"prePhonemeLength":0.1,"postPhonemeLength":0.1,"pauseLength":null,"pauseLengthScale":1,"outputSamplingRate":24000,"outputStereo":false,"kana":"ア'"}`,
+			wantExtra: []string{"pauseLength", "pauseLengthScale"},
+		},
+		{
+			name:  "アクセント句とモーラの未知のフィールド",
+			query: `{"accent_phrases":[{"moras":[{"text":"ア","consonant":null,"consonant_length":null,"vowel":"a","vowel_length":0.1,"pitch":5.5,"future_field":{"nested":[1,2]}}],"accent":1,"pause_mora":null,"is_interrogative":false,"phrase_extra":"x"}],"speedScale":1,"pitchScale":0,"intonationScale":1,"volumeScale":1,"prePhonemeLength":0.1,"postPhonemeLength":0.1,"outputSamplingRate":24000,"outputStereo":false}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := DecodeAudioQuery([]byte(tt.query))
+			if err != nil {
+				t.Fatalf("DecodeAudioQuery: %v", err)
+			}
+			for _, name := range tt.wantExtra {
+				if _, ok := q.Extra[name]; !ok {
+					t.Errorf("Extra[%q] がありません (Extra: %v)", name, q.Extra)
+				}
+			}
+
+			encoded, err := q.Encode()
+			if err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			var want, got any
+			if err := json.Unmarshal([]byte(tt.query), &want); err != nil {
+				t.Fatalf("入力のデコード: %v", err)
+			}
+			if err := json.Unmarshal(encoded, &got); err != nil {
+				t.Fatalf("出力のデコード: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("往復変換の結果が一致しません\n got: %s\nwant: %s", encoded, tt.query)
+			}
+		})
+	}
+}
+
+// TestAudioQueryCloneExtra は Clone した AudioQuery の Extra を変更しても、元のクエリに影響しないことを確認します。
+func TestAudioQueryCloneExtra(t *testing.T) {
+	q, err := DecodeAudioQuery([]byte(`{"accent_phrases":[{"moras":[{"text":"ア","vowel":"a","vowel_length":0.1,"pitch":5.5,"m":1}],"accent":1,"p":1}],"speedScale":1,"pauseLengthScale":1}`))
+	if err != nil {
+		t.Fatalf("DecodeAudioQuery: %v", err)
+	}
+
+	clone := q.Clone()
+	clone.Extra["pauseLengthScale"] = json.RawMessage("2")
+	clone.AccentPhrases[0].Extra["p"] = json.RawMessage("2")
+	clone.AccentPhrases[0].Moras[0].Extra["m"] = json.RawMessage("2")
+
+	if got := string(q.Extra["pauseLengthScale"]); got != "1" {
+		t.Errorf("元の AudioQuery.Extra = %s, want 1", got)
+	}
+	if got := string(q.AccentPhrases[0].Extra["p"]); got != "1" {
+		t.Errorf("元の AccentPhrase.Extra = %s, want 1", got)
+	}
+	if got := string(q.AccentPhrases[0].Moras[0].Extra["m"]); got != "1" {
+		t.Errorf("元の Mora.Extra = %s, want 1", got)
+	}
+}
@@ -22,13 +22,21 @@ import (
 // httpkit.ClientInterface を利用してリトライ機能を内包します。
 type Client struct {
 	client httpkit.ClientInterface
+	probe  httpkit.ClientInterface // 起動待ちの疎通確認用 (リトライなし)
 	apiURL string
 }
 
 // NewClient は新しいClientインスタンスを初期化します。
+// 5xx の応答のステータスコードとボディを ErrAPIResponse として返すため、HTTPクライアントには statusDoer を使用します。
 func NewClient(apiURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = httpkit.DefaultHTTPTimeout
+	}
+	doer := &statusDoer{client: &http.Client{Timeout: timeout}}
+
 	return &Client{
-		client: httpkit.New(timeout),
+		client: httpkit.New(timeout, httpkit.WithHTTPClient(doer)),
+		probe:  httpkit.New(timeout, httpkit.WithHTTPClient(doer), httpkit.WithMaxRetries(0)),
 		apiURL: apiURL,
 	}
 }
@@ -43,13 +51,8 @@ func (c *Client) buildURL(endpoint string) (*url.URL, error) {
 		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: fmt.Errorf("API URLのパース失敗: %w", err)}
 	}
 
-	// url.JoinPath は Go 1.19 以降で利用可能
-	u.Path, err = url.JoinPath(u.Path, endpoint)
-	if err != nil {
-		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: fmt.Errorf("エンドポイント結合失敗: %w", err)}
-	}
-
-	return u, nil
+	// endpoint はエスケープ済みのパスとして結合する (/user_dict_word/{word_uuid} の UUID は url.PathEscape 済み)
+	return u.JoinPath(endpoint), nil
 }
 
 // ----------------------------------------------------------------------
@@ -82,18 +85,28 @@ func (c *Client) RunAudioQuery(text string, styleID int, ctx context.Context) ([
 	// c.client.DoRequest() がリトライ、ステータスチェック、ボディ読み取りを処理
 	bodyBytes, err := c.client.DoRequest(req)
 	if err != nil {
-		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: err}
+		return nil, newRequestError(endpoint, err)
 	}
 
 	// 3. JSON構造の検証
-	var aqr AudioQueryResponse
-	if err := json.Unmarshal(bodyBytes, &aqr); err != nil {
+	var aq AudioQuery
+	if err := json.Unmarshal(bodyBytes, &aq); err != nil {
 		return nil, &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
 	}
 
 	return bodyBytes, nil
 }
 
+// GetAudioQuery は /audio_query APIを呼び出し、型付きの AudioQuery を返します。
+// 返されたクエリは編集したうえで SynthesizeAudioQuery に渡すことができます。
+func (c *Client) GetAudioQuery(ctx context.Context, text string, styleID int) (*AudioQuery, error) {
+	bodyBytes, err := c.RunAudioQuery(text, styleID, ctx)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeAudioQuery(bodyBytes)
+}
+
 // RunSynthesis は /synthesis APIを呼び出し、WAV形式の音声データを返します。
 func (c *Client) RunSynthesis(queryBody []byte, styleID int, ctx context.Context) ([]byte, error) {
 	const endpoint = "/synthesis"
@@ -122,7 +135,7 @@ func (c *Client) RunSynthesis(queryBody []byte, styleID int, ctx context.Context
 	// 3. リクエスト実行
 	wavData, err := c.client.DoRequest(req)
 	if err != nil {
-		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: err}
+		return nil, newRequestError(endpoint, err)
 	}
 
 	// 4. データ検証
@@ -136,6 +149,15 @@ func (c *Client) RunSynthesis(queryBody []byte, styleID int, ctx context.Context
 	return wavData, nil
 }
 
+// SynthesizeAudioQuery は AudioQuery をエンコードして /synthesis APIを呼び出し、WAV形式の音声データを返します。
+func (c *Client) SynthesizeAudioQuery(ctx context.Context, query *AudioQuery, styleID int) ([]byte, error) {
+	queryBody, err := query.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return c.RunSynthesis(queryBody, styleID, ctx)
+}
+
 // GetSpeakers は /speakers APIを呼び出し、VOICEVOXエンジンが提供する
 // 全てのスピーカー情報（JSONバイトスライス）を返します。
 func (c *Client) GetSpeakers(ctx context.Context) ([]byte, error) {
@@ -151,7 +173,7 @@ func (c *Client) GetSpeakers(ctx context.Context) ([]byte, error) {
 	// 2. httpkit.FetchBytes を使用してリクエスト実行
 	bodyBytes, err := c.client.FetchBytes(ctx, speakersURL)
 	if err != nil {
-		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: err}
+		return nil, newRequestError(endpoint, err)
 	}
 
 	return bodyBytes, nil
@@ -0,0 +1,14 @@
+package api
+
+import "time"
+
+// ----------------------------------------------------------------------
+// 起動待ちの既定値
+// ----------------------------------------------------------------------
+
+const (
+	// WaitReady の既定の待機間隔。失敗するたびに DefaultReadyMultiplier 倍し、DefaultReadyMaxInterval で頭打ちにします。
+	DefaultReadyInitialInterval = 500 * time.Millisecond
+	DefaultReadyMaxInterval     = 5 * time.Second
+	DefaultReadyMultiplier      = 2.0
+)
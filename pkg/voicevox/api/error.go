@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 )
 
 // ErrAPINetwork はAPI呼び出しにおける通信エラーやリトライ後の最終失敗を示すカスタムエラー型です。
@@ -14,14 +15,28 @@ func (e *ErrAPINetwork) Error() string {
 	return fmt.Sprintf("API通信エラー (%s): %v", e.Endpoint, e.WrappedErr)
 }
 
+// Unwrap は原因となったエラーを返します (errors.Is/As 用)。
+func (e *ErrAPINetwork) Unwrap() error {
+	return e.WrappedErr
+}
+
 // ErrAPIResponse はAPIが 4xx や 5xx などの異常なステータスコードを返したことを示します。
+// VOICEVOXエンジンのエラー応答 ({"detail": ...}) は Detail にデコードされます。
 type ErrAPIResponse struct {
 	Endpoint   string
 	StatusCode int
 	Body       string
+	// Detail は応答ボディをデコードした内容です。ボディが VOICEVOX のエラー形式でない場合は nil です。
+	Detail *ErrorDetail
+	// WrappedErr は httpkit が返した元のエラーです (リトライの経緯を含みます)。
+	WrappedErr error
 }
 
 func (e *ErrAPIResponse) Error() string {
+	if summary := e.Detail.summary(); summary != "" {
+		return fmt.Sprintf("API応答エラー (%s)。ステータスコード %d: %s", e.Endpoint, e.StatusCode, summary)
+	}
+
 	// 応答ボディが長すぎる場合は切り詰める
 	bodyDisplay := e.Body
 	if len(bodyDisplay) > 100 {
@@ -30,6 +45,22 @@ func (e *ErrAPIResponse) Error() string {
 	return fmt.Sprintf("API応答エラー (%s)。ステータスコード %d: %s", e.Endpoint, e.StatusCode, bodyDisplay)
 }
 
+// Unwrap は httpkit が返した元のエラーを返します (errors.Is/As 用)。
+func (e *ErrAPIResponse) Unwrap() error {
+	return e.WrappedErr
+}
+
+// IsInputError は、テキストやカナ、パラメータなどリクエストの内容の誤りによる失敗 (400, 422) であるかを返します。
+// 同じリクエストを再送しても成功しないため、入力を修正する必要があります。
+func (e *ErrAPIResponse) IsInputError() bool {
+	return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+}
+
+// IsServerError は、エンジン側の障害 (5xx) による失敗であるかを返します。
+func (e *ErrAPIResponse) IsServerError() bool {
+	return e.StatusCode >= http.StatusInternalServerError
+}
+
 // ErrInvalidJSON はAPI応答やデータが期待されるJSON形式でなかったことを示します。
 type ErrInvalidJSON struct {
 	Details    string
@@ -39,3 +70,23 @@ type ErrInvalidJSON struct {
 func (e *ErrInvalidJSON) Error() string {
 	return fmt.Sprintf("不正なJSONデータ: %s (詳細: %v)", e.Details, e.WrappedErr)
 }
+
+// Unwrap は原因となったエラーを返します (errors.Is/As 用)。
+func (e *ErrInvalidJSON) Unwrap() error {
+	return e.WrappedErr
+}
+
+// ErrEngineNotReady は WaitReady の期限内にエンジンが応答可能にならなかったことを示します。
+type ErrEngineNotReady struct {
+	Attempts int
+	LastErr  error
+}
+
+func (e *ErrEngineNotReady) Error() string {
+	return fmt.Sprintf("VOICEVOXエンジンが応答可能になりませんでした (試行回数: %d回, 最終エラー: %v)", e.Attempts, e.LastErr)
+}
+
+// Unwrap は最後の試行で発生したエラーを返します (errors.Is/As 用)。
+func (e *ErrEngineNotReady) Unwrap() error {
+	return e.LastErr
+}
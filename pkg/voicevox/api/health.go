@@ -0,0 +1,135 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// ----------------------------------------------------------------------
+// エンジン情報API
+// ----------------------------------------------------------------------
+
+// GetVersion は /version APIを呼び出し、エンジンのバージョン (例: "0.14.0") を返します。
+func (c *Client) GetVersion(ctx context.Context) (string, error) {
+	return c.fetchVersion(ctx, c.client)
+}
+
+// GetEngineManifest は /engine_manifest APIを呼び出し、エンジンのマニフェストを返します。
+func (c *Client) GetEngineManifest(ctx context.Context) (*EngineManifest, error) {
+	const endpoint = "/engine_manifest"
+
+	bodyBytes, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest EngineManifest
+	if err := json.Unmarshal(bodyBytes, &manifest); err != nil {
+		return nil, &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
+	}
+	return &manifest, nil
+}
+
+// GetSupportedDevices は /supported_devices APIを呼び出し、エンジンが利用可能なデバイスを返します。
+func (c *Client) GetSupportedDevices(ctx context.Context) (*SupportedDevices, error) {
+	const endpoint = "/supported_devices"
+
+	bodyBytes, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices SupportedDevices
+	if err := json.Unmarshal(bodyBytes, &devices); err != nil {
+		return nil, &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
+	}
+	return &devices, nil
+}
+
+// fetchVersion は指定された HTTP クライアントで /version を取得します。
+func (c *Client) fetchVersion(ctx context.Context, client httpkit.ClientInterface) (string, error) {
+	const endpoint = "/version"
+
+	u, err := c.buildURL(endpoint)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", &ErrAPINetwork{Endpoint: endpoint, WrappedErr: fmt.Errorf("リクエスト構築失敗: %w", err)}
+	}
+
+	bodyBytes, err := client.DoRequest(req)
+	if err != nil {
+		return "", newRequestError(endpoint, err)
+	}
+
+	// /version はJSON文字列 ("0.14.0") を返す
+	var version string
+	if err := json.Unmarshal(bodyBytes, &version); err != nil {
+		return "", &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
+	}
+	return version, nil
+}
+
+// ----------------------------------------------------------------------
+// 起動待ち
+// ----------------------------------------------------------------------
+
+// Backoff は WaitReady の再試行間隔を指定します。
+// ゼロ値のフィールドには既定値 (DefaultReadyInitialInterval など) が使用されます。
+type Backoff struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
+// withDefaults はゼロ値のフィールドを既定値で補った Backoff を返します。
+func (b Backoff) withDefaults() Backoff {
+	if b.InitialInterval <= 0 {
+		b.InitialInterval = DefaultReadyInitialInterval
+	}
+	if b.MaxInterval <= 0 {
+		b.MaxInterval = DefaultReadyMaxInterval
+	}
+	if b.MaxInterval < b.InitialInterval {
+		b.MaxInterval = b.InitialInterval
+	}
+	if b.Multiplier < 1 {
+		b.Multiplier = DefaultReadyMultiplier
+	}
+	return b
+}
+
+// WaitReady は /version が応答するまでエンジンへの疎通確認を繰り返し、エンジンのバージョンを返します。
+// 疎通確認は httpkit のリトライを使わずに1回ずつ行い、失敗するたびに backoff に従って待機します。
+// 待機の期限は ctx で指定します。期限までに応答がない場合は *ErrEngineNotReady を返します。
+func (c *Client) WaitReady(ctx context.Context, backoff Backoff) (string, error) {
+	backoff = backoff.withDefaults()
+	interval := backoff.InitialInterval
+
+	for attempt := 1; ; attempt++ {
+		version, err := c.fetchVersion(ctx, c.probe)
+		if err == nil {
+			return version, nil
+		}
+
+		slog.DebugContext(ctx, "VOICEVOXエンジンの起動を待機しています。", "attempt", attempt, "retry_in", interval.String(), "error", err)
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", &ErrEngineNotReady{Attempts: attempt, LastErr: err}
+		case <-timer.C:
+		}
+
+		interval = min(time.Duration(float64(interval)*backoff.Multiplier), backoff.MaxInterval)
+	}
+}
@@ -1,12 +1,175 @@
 package api
 
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
 // ----------------------------------------------------------------------
 // データモデル (API応答)
 // ----------------------------------------------------------------------
 
-// AudioQueryResponse は /audio_query APIの応答構造の一部に対応する型です。
-type AudioQueryResponse struct {
-	AccentPhrases []map[string]interface{} `json:"accent_phrases"`
-	SpeedScale    float64                  `json:"speedScale"`
-	// ... 他のフィールドは必要に応じて追加
+// AudioQuery は /audio_query APIの応答であり、/synthesis APIの入力となる音声合成クエリです。
+// 未知のフィールドは Extra に保持され、JSONの往復変換で失われません。
+type AudioQuery struct {
+	AccentPhrases      []AccentPhrase `json:"accent_phrases"`
+	SpeedScale         float64        `json:"speedScale"`
+	PitchScale         float64        `json:"pitchScale"`
+	IntonationScale    float64        `json:"intonationScale"`
+	VolumeScale        float64        `json:"volumeScale"`
+	PrePhonemeLength   float64        `json:"prePhonemeLength"`
+	PostPhonemeLength  float64        `json:"postPhonemeLength"`
+	OutputSamplingRate int            `json:"outputSamplingRate"`
+	OutputStereo       bool           `json:"outputStereo"`
+	Kana               *string        `json:"kana,omitempty"`
+
+	// Extra は上記以外のフィールド (例: pauseLength, pauseLengthScale) を生のJSONのまま保持します。
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AccentPhrase はアクセント句を表します。
+type AccentPhrase struct {
+	Moras           []Mora `json:"moras"`
+	Accent          int    `json:"accent"`
+	PauseMora       *Mora  `json:"pause_mora"`
+	IsInterrogative bool   `json:"is_interrogative"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Mora はモーラ（発音の単位）を表します。
+// 子音を持たないモーラでは Consonant と ConsonantLength が nil になります。
+type Mora struct {
+	Text            string   `json:"text"`
+	Consonant       *string  `json:"consonant"`
+	ConsonantLength *float64 `json:"consonant_length"`
+	Vowel           string   `json:"vowel"`
+	VowelLength     float64  `json:"vowel_length"`
+	Pitch           float64  `json:"pitch"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// AudioQueryResponse は後方互換のための別名です。
+//
+// Deprecated: AudioQuery を使用してください。
+type AudioQueryResponse = AudioQuery
+
+// Prosody は AudioQuery の話速・音高・抑揚・音量を上書きするための設定です。
+// nil のフィールドは上書きせず、/audio_query が返した値をそのまま使用します。
+type Prosody struct {
+	SpeedScale      *float64
+	PitchScale      *float64
+	IntonationScale *float64
+	VolumeScale     *float64
+}
+
+// ----------------------------------------------------------------------
+// データモデル (ユーザー辞書)
+// ----------------------------------------------------------------------
+
+// WordType は /user_dict_word の word_type パラメータ (品詞) です。
+type WordType string
+
+const (
+	WordTypeProperNoun WordType = "PROPER_NOUN" // 固有名詞
+	WordTypeCommonNoun WordType = "COMMON_NOUN" // 普通名詞
+	WordTypeVerb       WordType = "VERB"        // 動詞
+	WordTypeAdjective  WordType = "ADJECTIVE"   // 形容詞
+	WordTypeSuffix     WordType = "SUFFIX"      // 語尾
+)
+
+// UserDictWord は /user_dict が返すユーザー辞書の単語です。/import_user_dict の入力にも使用されます。
+type UserDictWord struct {
+	Surface               string `json:"surface"`
+	Priority              int    `json:"priority"`
+	ContextID             int    `json:"context_id"`
+	PartOfSpeech          string `json:"part_of_speech"`
+	PartOfSpeechDetail1   string `json:"part_of_speech_detail_1"`
+	PartOfSpeechDetail2   string `json:"part_of_speech_detail_2"`
+	PartOfSpeechDetail3   string `json:"part_of_speech_detail_3"`
+	InflectionalType      string `json:"inflectional_type"`
+	InflectionalForm      string `json:"inflectional_form"`
+	Stem                  string `json:"stem"`
+	Yomi                  string `json:"yomi"`
+	Pronunciation         string `json:"pronunciation"`
+	AccentType            int    `json:"accent_type"`
+	MoraCount             *int   `json:"mora_count,omitempty"`
+	AccentAssociativeRule string `json:"accent_associative_rule"`
+}
+
+// UserDict は単語のUUIDをキーとするユーザー辞書です。
+type UserDict map[string]UserDictWord
+
+// WordInput は /user_dict_word で単語を追加・更新する際のパラメータです。
+// WordType と Priority は省略可能で、省略時はエンジンの既定値 (固有名詞、優先度5) が使用されます。
+type WordInput struct {
+	Surface       string   // 表層形 (表記)
+	Pronunciation string   // 読み (カタカナ)
+	AccentType    int      // アクセント核の位置 (0 は平板型)
+	WordType      WordType // 品詞
+	Priority      *int     // 優先度 (0〜10)
+}
+
+// ----------------------------------------------------------------------
+// エンジン情報
+// ----------------------------------------------------------------------
+
+// EngineManifest は /engine_manifest が返すエンジンのマニフェストです。
+// 利用規約やライセンス情報など、ここで定義していないフィールドは読み捨てます。
+type EngineManifest struct {
+	ManifestVersion     string          `json:"manifest_version"`
+	Name                string          `json:"name"`
+	BrandName           string          `json:"brand_name"`
+	UUID                string          `json:"uuid"`
+	URL                 string          `json:"url"`
+	DefaultSamplingRate int             `json:"default_sampling_rate"`
+	FrameRate           float64         `json:"frame_rate"`
+	SupportedFeatures   map[string]bool `json:"supported_features"`
+}
+
+// SupportedDevices は /supported_devices が返す、エンジンが利用可能なデバイスです。
+type SupportedDevices struct {
+	CPU  bool `json:"cpu"`
+	CUDA bool `json:"cuda"`
+	DML  bool `json:"dml"`
+}
+
+// ----------------------------------------------------------------------
+// エラー応答
+// ----------------------------------------------------------------------
+
+// ErrorDetail はVOICEVOXエンジンのエラー応答 ({"detail": ...}) をデコードした内容です。
+// detail の形式はエラーの種類によって異なるため、該当するフィールドのみが設定されます。
+type ErrorDetail struct {
+	// Message は detail が文字列の場合の内容です (例: ユーザー辞書の操作エラー、話者が見つからない場合)。
+	Message string `json:"message,omitempty"`
+	// Validation は detail がリクエストの検証エラーの配列の場合の内容です (422)。
+	Validation []ValidationDetail `json:"validation,omitempty"`
+	// KanaParse は AquesTalk 風記法のカナの解析に失敗した場合の内容です (400)。
+	KanaParse *KanaParseDetail `json:"kana_parse,omitempty"`
+}
+
+// ValidationDetail はリクエストの検証エラー (detail[]) の1件です。
+type ValidationDetail struct {
+	Loc  []any  `json:"loc"`  // エラーの位置 (例: ["query", "text"])
+	Msg  string `json:"msg"`  // エラーの内容
+	Type string `json:"type"` // エラーの種類 (例: "value_error.missing")
+}
+
+// Field は Loc を "." で連結した文字列 (例: "query.text") を返します。
+func (d ValidationDetail) Field() string {
+	parts := make([]string, 0, len(d.Loc))
+	for _, loc := range d.Loc {
+		parts = append(parts, fmt.Sprint(loc))
+	}
+	return strings.Join(parts, ".")
+}
+
+// KanaParseDetail はカナの解析エラー (ParseKanaBadRequest) の内容です。
+type KanaParseDetail struct {
+	Text      string         `json:"text"`       // エラーメッセージ
+	ErrorName string         `json:"error_name"` // エラーの種類 (例: "ACCENT_NOTFOUND")
+	ErrorArgs map[string]any `json:"error_args"` // エラーの引数 (例: {"text": "ア'ア'"})
 }
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// ----------------------------------------------------------------------
+// エラー応答の解析
+// ----------------------------------------------------------------------
+
+// statusDoer は 5xx の応答を型付きのエラー (serverStatusError) に変換する httpkit.Doer です。
+// httpkit は 5xx を型のないエラーとして返すため、ステータスコードとボディをここで保持します。
+// エラーを返しても httpkit のリトライ対象 (NonRetryableHTTPError 以外) として扱われます。
+type statusDoer struct {
+	client *http.Client
+}
+
+// Do はリクエストを実行し、5xx の場合はボディを読み取って serverStatusError を返します。
+func (d *statusDoer) Do(req *http.Request) (*http.Response, error) {
+	resp, err := d.client.Do(req)
+	if err != nil || resp.StatusCode < http.StatusInternalServerError {
+		return resp, err
+	}
+
+	body, readErr := httpkit.HandleLimitedResponse(resp, httpkit.MaxResponseBodySize)
+	if readErr != nil {
+		body = nil
+	}
+	return nil, &serverStatusError{StatusCode: resp.StatusCode, Body: body}
+}
+
+// serverStatusError はエンジンが 5xx を返したことを示します。newRequestError で ErrAPIResponse に変換されます。
+type serverStatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *serverStatusError) Error() string {
+	return fmt.Sprintf("HTTPステータスコードエラー (5xx リトライ対象): %d, 詳細: %s", e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+
+// newRequestError は DoRequest / FetchBytes が返したエラーを、ステータスコードの有無に応じて分類します。
+// エンジンが応答を返した場合は ErrAPIResponse を、通信自体に失敗した場合は ErrAPINetwork を返します。
+func newRequestError(endpoint string, err error) error {
+	var httpErr *httpkit.NonRetryableHTTPError
+	if errors.As(err, &httpErr) {
+		return newResponseError(endpoint, httpErr.StatusCode, httpErr.Body, err)
+	}
+
+	var statusErr *serverStatusError
+	if errors.As(err, &statusErr) {
+		return newResponseError(endpoint, statusErr.StatusCode, statusErr.Body, err)
+	}
+
+	return &ErrAPINetwork{Endpoint: endpoint, WrappedErr: err}
+}
+
+// newResponseError は応答ボディをデコードして ErrAPIResponse を構築します。
+func newResponseError(endpoint string, statusCode int, body []byte, err error) *ErrAPIResponse {
+	return &ErrAPIResponse{
+		Endpoint:   endpoint,
+		StatusCode: statusCode,
+		Body:       strings.TrimSpace(string(body)),
+		Detail:     decodeErrorDetail(body),
+		WrappedErr: err,
+	}
+}
+
+// decodeErrorDetail は VOICEVOX のエラー応答ボディ ({"detail": ...}) をデコードします。
+// detail は文字列、検証エラーの配列、カナの解析エラーのオブジェクトのいずれかです。
+// ボディがこれらの形式でない場合は nil を返します。
+func decodeErrorDetail(body []byte) *ErrorDetail {
+	var envelope struct {
+		Detail json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || len(envelope.Detail) == 0 {
+		return nil
+	}
+
+	raw := bytes.TrimSpace(envelope.Detail)
+	switch {
+	case bytes.HasPrefix(raw, []byte(`"`)):
+		var message string
+		if err := json.Unmarshal(raw, &message); err == nil {
+			return &ErrorDetail{Message: message}
+		}
+	case bytes.HasPrefix(raw, []byte(`[`)):
+		var validation []ValidationDetail
+		if err := json.Unmarshal(raw, &validation); err == nil {
+			return &ErrorDetail{Validation: validation}
+		}
+	case bytes.HasPrefix(raw, []byte(`{`)):
+		var kana KanaParseDetail
+		if err := json.Unmarshal(raw, &kana); err == nil && (kana.Text != "" || kana.ErrorName != "") {
+			return &ErrorDetail{KanaParse: &kana}
+		}
+	}
+	return nil
+}
+
+// summary はエラーメッセージに使用する detail の要約を返します。
+func (d *ErrorDetail) summary() string {
+	if d == nil {
+		return ""
+	}
+
+	switch {
+	case len(d.Validation) > 0:
+		items := make([]string, 0, len(d.Validation))
+		for _, v := range d.Validation {
+			items = append(items, fmt.Sprintf("%s: %s", v.Field(), v.Msg))
+		}
+		return "入力の検証エラー (" + strings.Join(items, "; ") + ")"
+	case d.KanaParse != nil:
+		return fmt.Sprintf("カナの解析エラー %s (%s)", d.KanaParse.ErrorName, d.KanaParse.Text)
+	default:
+		return d.Message
+	}
+}
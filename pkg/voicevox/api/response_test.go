@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+func TestDecodeErrorDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *ErrorDetail
+	}{
+		{
+			name: "検証エラーの配列",
+			body: `{"detail":[{"loc":["query","text"],"msg":"field required","type":"value_error.missing"}]}`,
+			want: &ErrorDetail{Validation: []ValidationDetail{
+				{Loc: []any{"query", "text"}, Msg: "field required", Type: "value_error.missing"},
+			}},
+		},
+		{
+			name: "カナの解析エラー",
+			body: `{"detail":{"text":"アクセントを指定していないアクセント句があります: ア","error_name":"ACCENT_NOTFOUND","error_args":{"text":"ア"}}}`,
+			want: &ErrorDetail{KanaParse: &KanaParseDetail{
+				Text:      "アクセントを指定していないアクセント句があります: ア",
+				ErrorName: "ACCENT_NOTFOUND",
+				ErrorArgs: map[string]any{"text": "ア"},
+			}},
+		},
+		{
+			name: "文字列のメッセージ",
+			body: `{"detail":"該当する話者が見つかりません"}`,
+			want: &ErrorDetail{Message: "該当する話者が見つかりません"},
+		},
+		{name: "JSONではないボディ", body: `Internal Server Error`, want: nil},
+		{name: "detail のないJSON", body: `{"error":"x"}`, want: nil},
+		{name: "未知の形式のオブジェクト", body: `{"detail":{"foo":1}}`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeErrorDetail([]byte(tt.body))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeErrorDetail(%s) = %+v, want %+v", tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationDetailField(t *testing.T) {
+	d := ValidationDetail{Loc: []any{"body", "accent_phrases", float64(0), "moras"}}
+	if got, want := d.Field(), "body.accent_phrases.0.moras"; got != want {
+		t.Errorf("Field() = %q, want %q", got, want)
+	}
+}
+
+// TestClientResponseErrors は、エンジンが返したステータスコードとボディが ErrAPIResponse に格納され、
+// 応答のない通信エラーと区別できることを確認します。
+func TestClientResponseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantStatus int
+		wantInput  bool
+		wantServer bool
+		wantCalls  int32
+	}{
+		{
+			name:       "422 は入力の誤り (再試行しない)",
+			status:     http.StatusUnprocessableEntity,
+			body:       `{"detail":[{"loc":["query","text"],"msg":"field required","type":"value_error.missing"}]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantInput:  true,
+			wantCalls:  1,
+		},
+		{
+			name:       "503 はエンジンの障害 (httpkit が再試行する)",
+			status:     http.StatusServiceUnavailable,
+			body:       `{"detail":"busy"}`,
+			wantStatus: http.StatusServiceUnavailable,
+			wantServer: true,
+			wantCalls:  3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			c := newTestClient(srv.URL, 2)
+			_, err := c.RunAudioQuery("テスト", 1, context.Background())
+
+			var respErr *ErrAPIResponse
+			if !errors.As(err, &respErr) {
+				t.Fatalf("RunAudioQuery error = %v (%T), want *ErrAPIResponse", err, err)
+			}
+			if respErr.StatusCode != tt.wantStatus || respErr.Endpoint != "/audio_query" {
+				t.Errorf("ErrAPIResponse = {Endpoint: %s, StatusCode: %d}, want {/audio_query, %d}", respErr.Endpoint, respErr.StatusCode, tt.wantStatus)
+			}
+			if respErr.Detail == nil {
+				t.Errorf("Detail = nil, want decoded %s", tt.body)
+			}
+			if respErr.IsInputError() != tt.wantInput || respErr.IsServerError() != tt.wantServer {
+				t.Errorf("IsInputError() = %v, IsServerError() = %v; want %v, %v", respErr.IsInputError(), respErr.IsServerError(), tt.wantInput, tt.wantServer)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("リクエスト回数 = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close() // 接続を拒否させる
+
+	_, err := newTestClient(url, 0).RunAudioQuery("テスト", 1, context.Background())
+
+	var networkErr *ErrAPINetwork
+	var respErr *ErrAPIResponse
+	if !errors.As(err, &networkErr) || errors.As(err, &respErr) {
+		t.Fatalf("RunAudioQuery error = %v (%T), want *ErrAPINetwork only", err, err)
+	}
+}
+
+// newTestClient は再試行の間隔を短くした Client を作成します。
+func newTestClient(apiURL string, maxRetries uint64) *Client {
+	doer := &statusDoer{client: &http.Client{Timeout: time.Second}}
+	return &Client{
+		client: httpkit.New(time.Second,
+			httpkit.WithHTTPClient(doer),
+			httpkit.WithMaxRetries(maxRetries),
+			httpkit.WithInitialInterval(time.Millisecond),
+			httpkit.WithMaxInterval(time.Millisecond)),
+		apiURL: apiURL,
+	}
+}
+
+// TestNewClientServerError は NewClient が構築するクライアントで、5xx が ErrAPIResponse になることを確認します。
+func TestNewClientServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"detail":"engine crashed"}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL, time.Second)
+	_, err := c.fetchVersion(context.Background(), c.probe)
+
+	var respErr *ErrAPIResponse
+	if !errors.As(err, &respErr) || !respErr.IsServerError() || respErr.Detail.Message != "engine crashed" {
+		t.Fatalf("fetchVersion error = %v (%T), want *ErrAPIResponse with status 500", err, err)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ----------------------------------------------------------------------
+// ユーザー辞書API
+// ----------------------------------------------------------------------
+
+// GetUserDict は /user_dict APIを呼び出し、エンジンに登録されているユーザー辞書を返します。
+func (c *Client) GetUserDict(ctx context.Context) (UserDict, error) {
+	const endpoint = "/user_dict"
+
+	bodyBytes, err := c.doRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var dict UserDict
+	if err := json.Unmarshal(bodyBytes, &dict); err != nil {
+		return nil, &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
+	}
+	return dict, nil
+}
+
+// AddUserDictWord は /user_dict_word APIを呼び出して単語を追加し、割り当てられた単語のUUIDを返します。
+func (c *Client) AddUserDictWord(ctx context.Context, word WordInput) (string, error) {
+	const endpoint = "/user_dict_word"
+
+	bodyBytes, err := c.doRequest(ctx, http.MethodPost, endpoint, word.query(), nil)
+	if err != nil {
+		return "", err
+	}
+
+	var wordUUID string
+	if err := json.Unmarshal(bodyBytes, &wordUUID); err != nil {
+		return "", &ErrInvalidJSON{Details: fmt.Sprintf("%s応答JSONのデコード", endpoint), WrappedErr: err}
+	}
+	return wordUUID, nil
+}
+
+// UpdateUserDictWord は /user_dict_word/{word_uuid} APIを呼び出し、既存の単語を更新します。
+func (c *Client) UpdateUserDictWord(ctx context.Context, wordUUID string, word WordInput) error {
+	endpoint := "/user_dict_word/" + url.PathEscape(wordUUID)
+	_, err := c.doRequest(ctx, http.MethodPut, endpoint, word.query(), nil)
+	return err
+}
+
+// DeleteUserDictWord は /user_dict_word/{word_uuid} APIを呼び出し、単語を削除します。
+func (c *Client) DeleteUserDictWord(ctx context.Context, wordUUID string) error {
+	endpoint := "/user_dict_word/" + url.PathEscape(wordUUID)
+	_, err := c.doRequest(ctx, http.MethodDelete, endpoint, nil, nil)
+	return err
+}
+
+// ImportUserDict は /import_user_dict APIを呼び出し、辞書をエンジンにインポートします。
+// override が true の場合、UUIDが重複する単語はインポートする内容で上書きされます。
+func (c *Client) ImportUserDict(ctx context.Context, dict UserDict, override bool) error {
+	const endpoint = "/import_user_dict"
+
+	body, err := json.Marshal(dict)
+	if err != nil {
+		return &ErrInvalidJSON{Details: "ユーザー辞書のエンコード", WrappedErr: err}
+	}
+
+	query := url.Values{}
+	query.Set("override", strconv.FormatBool(override))
+	_, err = c.doRequest(ctx, http.MethodPost, endpoint, query, body)
+	return err
+}
+
+// query は WordInput を /user_dict_word のクエリパラメータに変換します。
+func (w WordInput) query() url.Values {
+	q := url.Values{}
+	q.Set("surface", w.Surface)
+	q.Set("pronunciation", w.Pronunciation)
+	q.Set("accent_type", strconv.Itoa(w.AccentType))
+	if w.WordType != "" {
+		q.Set("word_type", string(w.WordType))
+	}
+	if w.Priority != nil {
+		q.Set("priority", strconv.Itoa(*w.Priority))
+	}
+	return q
+}
+
+// ----------------------------------------------------------------------
+// ヘルパー: リクエストの構築と実行
+// ----------------------------------------------------------------------
+
+// doRequest はエンドポイントとクエリパラメータからリクエストを構築して実行し、応答ボディを返します。
+// body が nil でない場合は JSON として送信します。
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, query url.Values, body []byte) ([]byte, error) {
+	u, err := c.buildURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if query != nil {
+		u.RawQuery = query.Encode()
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return nil, &ErrAPINetwork{Endpoint: endpoint, WrappedErr: fmt.Errorf("リクエスト構築失敗: %w", err)}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	bodyBytes, err := c.client.DoRequest(req)
+	if err != nil {
+		return nil, newRequestError(endpoint, err)
+	}
+	return bodyBytes, nil
+}
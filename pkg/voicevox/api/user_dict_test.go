@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordedRequest はテスト用サーバーが受け取ったリクエストです。
+type recordedRequest struct {
+	method string
+	path   string // エスケープされたパス
+	query  string
+	body   string
+}
+
+// newUserDictServer は受け取ったリクエストを記録し、status と body を返すテスト用サーバーを起動します。
+func newUserDictServer(t *testing.T, status int, body string) (*Client, *recordedRequest) {
+	t.Helper()
+
+	got := &recordedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		*got = recordedRequest{method: r.Method, path: r.URL.EscapedPath(), query: r.URL.RawQuery, body: string(data)}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL, time.Second), got
+}
+
+func TestUserDictRequests(t *testing.T) {
+	priority := 7
+	word := WordInput{Surface: "ずんだ 餅", Pronunciation: "ズンダモチ", AccentType: 3}
+	importDict := UserDict{"uuid-1": {Surface: "ずんだ餅", Pronunciation: "ズンダモチ", AccentType: 3}}
+	importBody, err := json.Marshal(importDict)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		call         func(c *Client) (any, error)
+		responseBody string
+		want         recordedRequest
+		wantResult   any
+	}{
+		{
+			name:         "GetUserDict",
+			call:         func(c *Client) (any, error) { return c.GetUserDict(context.Background()) },
+			responseBody: `{"uuid-1":{"surface":"ずんだ餅","pronunciation":"ズンダモチ","accent_type":3,"priority":5}}`,
+			want:         recordedRequest{method: http.MethodGet, path: "/user_dict"},
+			wantResult:   UserDict{"uuid-1": {Surface: "ずんだ餅", Pronunciation: "ズンダモチ", AccentType: 3, Priority: 5}},
+		},
+		{
+			name:         "AddUserDictWord (省略可能なパラメータなし)",
+			call:         func(c *Client) (any, error) { return c.AddUserDictWord(context.Background(), word) },
+			responseBody: `"new-uuid"`,
+			want: recordedRequest{
+				method: http.MethodPost, path: "/user_dict_word",
+				query: "accent_type=3&pronunciation=%E3%82%BA%E3%83%B3%E3%83%80%E3%83%A2%E3%83%81&surface=%E3%81%9A%E3%82%93%E3%81%A0+%E9%A4%85",
+			},
+			wantResult: "new-uuid",
+		},
+		{
+			name: "UpdateUserDictWord (品詞と優先度を指定)",
+			call: func(c *Client) (any, error) {
+				w := word
+				w.WordType, w.Priority = WordTypeCommonNoun, &priority
+				return nil, c.UpdateUserDictWord(context.Background(), "uuid/1", w)
+			},
+			want: recordedRequest{
+				method: http.MethodPut, path: "/user_dict_word/uuid%2F1",
+				query: "accent_type=3&priority=7&pronunciation=%E3%82%BA%E3%83%B3%E3%83%80%E3%83%A2%E3%83%81&surface=%E3%81%9A%E3%82%93%E3%81%A0+%E9%A4%85&word_type=COMMON_NOUN",
+			},
+		},
+		{
+			name: "DeleteUserDictWord",
+			call: func(c *Client) (any, error) { return nil, c.DeleteUserDictWord(context.Background(), "uuid-1") },
+			want: recordedRequest{method: http.MethodDelete, path: "/user_dict_word/uuid-1"},
+		},
+		{
+			name: "ImportUserDict",
+			call: func(c *Client) (any, error) {
+				return nil, c.ImportUserDict(context.Background(), importDict, true)
+			},
+			want: recordedRequest{method: http.MethodPost, path: "/import_user_dict", query: "override=true", body: string(importBody)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, got := newUserDictServer(t, http.StatusOK, tt.responseBody)
+			result, err := tt.call(c)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if *got != tt.want {
+				t.Errorf("リクエスト = %+v, want %+v", *got, tt.want)
+			}
+			if tt.wantResult != nil && !reflect.DeepEqual(result, tt.wantResult) {
+				t.Errorf("結果 = %+v, want %+v", result, tt.wantResult)
+			}
+		})
+	}
+}
+
+func TestUserDictErrors(t *testing.T) {
+	t.Run("エンジンのエラー応答", func(t *testing.T) {
+		c, _ := newUserDictServer(t, http.StatusUnprocessableEntity, `{"detail":"該当する単語が見つかりません"}`)
+		err := c.DeleteUserDictWord(context.Background(), "missing")
+
+		var respErr *ErrAPIResponse
+		if !errors.As(err, &respErr) {
+			t.Fatalf("DeleteUserDictWord error = %v (%T), want *ErrAPIResponse", err, err)
+		}
+		if respErr.Endpoint != "/user_dict_word/missing" || !respErr.IsInputError() || respErr.Detail == nil || respErr.Detail.Message != "該当する単語が見つかりません" {
+			t.Errorf("ErrAPIResponse = %+v, want 422 on /user_dict_word/missing with decoded detail", respErr)
+		}
+	})
+
+	t.Run("不正な応答JSON", func(t *testing.T) {
+		c, _ := newUserDictServer(t, http.StatusOK, `["not", "a", "dict"]`)
+		_, err := c.GetUserDict(context.Background())
+		if _, ok := err.(*ErrInvalidJSON); !ok {
+			t.Fatalf("GetUserDict error = %v (%T), want *ErrInvalidJSON", err, err)
+		}
+	})
+}
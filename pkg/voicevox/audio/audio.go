@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"time"
 )
 
 // --- WAV 関連のカスタムエラー型 ---
@@ -28,49 +29,170 @@ func (e *ErrInvalidWAVHeader) Error() string {
 	return fmt.Sprintf("WAVヘッダーが無効です: %s", e.Details)
 }
 
+// ErrFormatMismatch は、結合対象のWAVデータのフォーマットが出力フォーマットと一致せず、
+// 変換もできない場合に発生します。
+type ErrFormatMismatch struct {
+	Index    int       // 何番目のWAVファイルか
+	Expected WavFormat // 出力フォーマット
+	Actual   WavFormat // 対象WAVファイルのフォーマット
+	Details  string    // 変換に失敗した場合の詳細
+}
+
+func (e *ErrFormatMismatch) Error() string {
+	msg := fmt.Sprintf("WAVファイル #%d のフォーマット (%s) が出力フォーマット (%s) と一致しません", e.Index, e.Actual, e.Expected)
+	if e.Details != "" {
+		msg += ": " + e.Details
+	}
+	return msg
+}
+
+// CombineOption は WAV 結合時の動作を設定するオプションです。
+type CombineOption func(*combineConfig)
+
+type combineConfig struct {
+	convert    bool
+	sampleRate uint32
+	channels   uint16
+}
+
+// WithConversion はフォーマットの異なるWAVデータを出力フォーマットに変換してから結合します。
+// 変換は16bitリニアPCMのみ対応します。指定しない場合、フォーマットの不一致は ErrFormatMismatch になります。
+func WithConversion() CombineOption {
+	return func(c *combineConfig) {
+		c.convert = true
+	}
+}
+
+// WithOutputFormat は出力のサンプリングレートとチャンネル数を指定し、WithConversion を有効にします。
+// 0 を指定した項目は最初のWAVデータの値を使用します。
+func WithOutputFormat(sampleRate uint32, channels uint16) CombineOption {
+	return func(c *combineConfig) {
+		c.convert = true
+		c.sampleRate = sampleRate
+		c.channels = channels
+	}
+}
+
+// Clip は結合対象となる1区間を表します。
+// WAV が nil の場合は、Silence で指定された長さの無音区間として扱われます。
+type Clip struct {
+	WAV     []byte
+	Silence time.Duration
+}
+
 // CombineWavData は複数のWAVデータ（バイトスライス）を結合し、
 // 正しいヘッダーを持つ単一のWAVファイル（バイトスライス）を生成します。
 // 最初のWAVファイルからフォーマット情報（サンプリングレート、チャンネル数など）を抽出します。
-func CombineWavData(wavDataList [][]byte) ([]byte, error) {
-	if len(wavDataList) == 0 {
-		// ErrNoAudioData を利用
-		return nil, &ErrNoAudioData{}
+// 各WAVファイルの fmt チャンクは個別に解析され、フォーマットが異なる場合はエラー、
+// または WithConversion 指定時は出力フォーマットへの変換が行われます。
+func CombineWavData(wavDataList [][]byte, opts ...CombineOption) ([]byte, error) {
+	clips := make([]Clip, len(wavDataList))
+	for i, wavData := range wavDataList {
+		clips[i] = Clip{WAV: wavData}
+	}
+	return CombineClips(clips, opts...)
+}
+
+// Span は結合後の音声における1区間の開始・終了位置です。
+type Span struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// CombineClips は WAV データと無音区間の並びを結合し、単一のWAVファイルを生成します。
+// 出力フォーマットは最初の WAV データから決定され、無音区間はそのフォーマットに合わせたPCMデータとして生成されます。
+func CombineClips(clips []Clip, opts ...CombineOption) ([]byte, error) {
+	combined, _, err := CombineClipsWithTimeline(clips, opts...)
+	return combined, err
+}
+
+// CombineClipsWithTimeline は CombineClips と同様に結合を行い、
+// 各クリップが結合後の音声のどこに配置されたかを clips と同じ順序で返します。
+// 位置はサンプル数から算出されるため、字幕のタイムスタンプなどに利用できます。
+func CombineClipsWithTimeline(clips []Clip, opts ...CombineOption) ([]byte, []Span, error) {
+	cfg := &combineConfig{}
+	for _, opt := range opts {
+		opt(cfg)
 	}
 
 	// 1. 最初のWAVからフォーマット情報を抽出
-	firstWav := wavDataList[0]
-	// 最初のWAVファイルはインデックス0で解析
+	firstWavIndex := -1
+	for i, clip := range clips {
+		if clip.WAV != nil {
+			firstWavIndex = i
+			break
+		}
+	}
+	if firstWavIndex < 0 {
+		// ErrNoAudioData を利用
+		return nil, nil, &ErrNoAudioData{}
+	}
+
 	// 修正された extractAudioData が、fmt/data チャンクを動的に探索し、メタデータをスキップ
-	formatHeader, audioData, err := extractAudioData(firstWav, 0)
+	formatHeader, format, _, err := extractAudioData(clips[firstWavIndex].WAV, firstWavIndex)
 	if err != nil {
-		return nil, fmt.Errorf("最初のWAVファイルの解析に失敗しました: %w", err)
+		return nil, nil, fmt.Errorf("最初のWAVファイルの解析に失敗しました: %w", err)
 	}
 
-	// 2. すべてのオーディオデータを連結
+	// 出力フォーマットが指定されている場合は、ヘッダーを新たに生成する
+	if outputFormat := format.withLayout(cfg.sampleRate, cfg.channels); !outputFormat.Matches(format) {
+		if !outputFormat.isPCM16() {
+			return nil, nil, &ErrFormatMismatch{
+				Index:    firstWavIndex,
+				Expected: outputFormat,
+				Actual:   format,
+				Details:  "16bitリニアPCM以外の変換には対応していません",
+			}
+		}
+		format = outputFormat
+		formatHeader = buildFormatHeader(format)
+	}
+
+	// 2. すべてのオーディオデータを連結し、各クリップの位置を記録
 	var audioDataWriter bytes.Buffer
-	totalAudioSize := len(audioData)
-	audioDataWriter.Write(audioData)
-
-	// 2番目以降のWAVデータを処理
-	for i := 1; i < len(wavDataList); i++ {
-		currentWav := wavDataList[i]
-		// i + 1 は元のセグメント番号
-		_, currentAudioData, err := extractAudioData(currentWav, i+1)
-		if err != nil {
-			return nil, fmt.Errorf("WAVファイル #%d の解析に失敗しました: %w", i+1, err)
+	timeline := make([]Span, len(clips))
+	for i, clip := range clips {
+		start := format.frameOffset(audioDataWriter.Len())
+
+		if clip.WAV == nil {
+			// 無音区間: 出力フォーマットに合わせたPCMデータを生成
+			audioDataWriter.Write(silenceData(format, clip.Silence))
+		} else {
+			_, currentFormat, currentAudioData, err := extractAudioData(clip.WAV, i)
+			if err != nil {
+				return nil, nil, fmt.Errorf("WAVファイル #%d の解析に失敗しました: %w", i, err)
+			}
+			if currentAudioData, err = conformAudioData(currentAudioData, currentFormat, format, i, cfg.convert); err != nil {
+				return nil, nil, err
+			}
+			audioDataWriter.Write(currentAudioData)
 		}
 
-		audioDataWriter.Write(currentAudioData)
-		totalAudioSize += len(currentAudioData)
+		timeline[i] = Span{Start: start, End: format.frameOffset(audioDataWriter.Len())}
 	}
 
 	// 3. 結合されたデータと最初のフォーマットヘッダーから新しいWAVファイルを構築
-	combinedWavBytes, err := buildCombinedWav(formatHeader, audioDataWriter.Bytes(), totalAudioSize)
+	combinedWavBytes, err := buildCombinedWav(formatHeader, audioDataWriter.Bytes(), audioDataWriter.Len())
 	if err != nil {
-		return nil, fmt.Errorf("最終的なWAVファイルの構築に失敗しました: %w", err)
+		return nil, nil, fmt.Errorf("最終的なWAVファイルの構築に失敗しました: %w", err)
 	}
 
-	return combinedWavBytes, nil
+	return combinedWavBytes, timeline, nil
+}
+
+// ValidateWavData は WAV データの fmt/data チャンクを検証し、フォーマット情報を返します。
+func ValidateWavData(wavBytes []byte) (WavFormat, error) {
+	_, format, _, err := extractAudioData(wavBytes, -1)
+	return format, err
+}
+
+// WavDuration は WAV データの再生時間を、data チャンクのサンプル数から算出します。
+func WavDuration(wavBytes []byte) (time.Duration, error) {
+	_, format, audioData, err := extractAudioData(wavBytes, -1)
+	if err != nil {
+		return 0, err
+	}
+	return format.frameOffset(len(audioData)), nil
 }
 
 // ----------------------------------------------------------------------
@@ -79,11 +201,11 @@ func CombineWavData(wavDataList [][]byte) ([]byte, error) {
 
 // extractAudioData はWAVファイルバイトスライスからフォーマットヘッダー情報とオーディオデータ部分を抽出します。
 // fmt/data チャンクを動的に探索し、dataチャンクの直前までを formatHeader とします。
-func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, audioData []byte, err error) {
+func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, format WavFormat, audioData []byte, err error) {
 
 	// RIFFヘッダー (12バイト: RIFF + file size + WAVE) の存在確認
 	if len(wavBytes) < WavRiffHeaderSize {
-		return nil, nil, &ErrInvalidWAVHeader{
+		return nil, WavFormat{}, nil, &ErrInvalidWAVHeader{
 			Index:   index,
 			Details: fmt.Sprintf("WAVファイルサイズが短すぎます (RIFFヘッダー不足: %dバイト)", len(wavBytes)),
 		}
@@ -107,13 +229,26 @@ func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, audioDat
 		// チャンクサイズ (次の4バイト) を抽出 (リトルエンディアン)
 		chunkSize := binary.LittleEndian.Uint32(wavBytes[offset+DataChunkIDSize : offset+DataChunkHeaderSize])
 
-		// fmt チャンクの確認 (formatHeaderの整合性を確認するため)
-		if chunkID == "fmt " {
+		// fmt チャンクの確認 (formatHeaderの整合性を確認し、フォーマット情報を取得するため)
+		if chunkID == FmtChunkID {
+			fmtChunkStart := offset + DataChunkHeaderSize
+			fmtChunkEnd := fmtChunkStart + int(chunkSize)
+			if fmtChunkEnd > len(wavBytes) {
+				return nil, WavFormat{}, nil, &ErrInvalidWAVHeader{
+					Index:   index,
+					Details: "fmtチャンクのデータ長がファイルサイズを超過しています",
+				}
+			}
+
+			format, err = parseFmtChunk(wavBytes[fmtChunkStart:fmtChunkEnd], index)
+			if err != nil {
+				return nil, WavFormat{}, nil, err
+			}
 			fmtChunkFound = true
 		}
 
 		// data チャンクの確認
-		if chunkID == "data" {
+		if chunkID == DataChunkID {
 			dataChunkFound = true
 			dataChunkStart = offset // dataチャンクの開始位置を記録
 
@@ -122,7 +257,7 @@ func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, audioDat
 			audioDataEnd := audioDataStart + int(chunkSize)
 
 			if audioDataEnd > len(wavBytes) {
-				return nil, nil, &ErrInvalidWAVHeader{
+				return nil, WavFormat{}, nil, &ErrInvalidWAVHeader{
 					Index:   index,
 					Details: "dataチャンクのデータ長がファイルサイズを超過しています",
 				}
@@ -155,7 +290,7 @@ func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, audioDat
 			}
 			missingChunk += "'data'"
 		}
-		return nil, nil, &ErrInvalidWAVHeader{
+		return nil, WavFormat{}, nil, &ErrInvalidWAVHeader{
 			Index:   index,
 			Details: fmt.Sprintf("WAVファイル内に必要なチャンク (%s) が見つかりませんでした", missingChunk),
 		}
@@ -168,13 +303,32 @@ func extractAudioData(wavBytes []byte, index int) (formatHeader []byte, audioDat
 	// 抽出されたデータサイズがヘッダーの記載と一致するか最終確認
 	// このチェックはすでに data チャンクが見つかったブロック内で行われているが、冗長性を排除するため最終結果をチェック
 	if len(audioData) != int(binary.LittleEndian.Uint32(wavBytes[dataChunkStart+DataChunkIDSize:dataChunkStart+DataChunkHeaderSize])) {
-		return nil, nil, &ErrInvalidWAVHeader{
+		return nil, WavFormat{}, nil, &ErrInvalidWAVHeader{
 			Index:   index,
 			Details: "最終的な抽出データサイズがヘッダー記載サイズと一致しません",
 		}
 	}
 
-	return formatHeader, audioData, nil
+	return formatHeader, format, audioData, nil
+}
+
+// conformAudioData は audioData のフォーマットを出力フォーマットと照合し、必要に応じて変換します。
+func conformAudioData(audioData []byte, from, to WavFormat, index int, convert bool) ([]byte, error) {
+	if from.Matches(to) {
+		return audioData, nil
+	}
+
+	mismatch := &ErrFormatMismatch{Index: index, Expected: to, Actual: from}
+	if !convert {
+		return nil, mismatch
+	}
+
+	converted, err := ConvertPCM16(audioData, from, to)
+	if err != nil {
+		mismatch.Details = err.Error()
+		return nil, mismatch
+	}
+	return converted, nil
 }
 
 // buildCombinedWav はフォーマットヘッダー情報と結合されたオーディオデータから、
@@ -199,7 +353,7 @@ func buildCombinedWav(formatHeader, combinedAudioData []byte, totalAudioSize int
 	copy(combinedWav, formatHeader)
 
 	// dataチャンクヘッダー（"data" + size）を追加
-	copy(combinedWav[dataChunkStart:], []byte("data"))
+	copy(combinedWav[dataChunkStart:], []byte(DataChunkID))
 
 	// RIFFチャンクサイズ (File Size - 8) の更新 (4-8バイト目)
 	binary.LittleEndian.PutUint32(combinedWav[RiffChunkSizeOffset:RiffChunkSizeOffset+4], uint32(fileSize))
@@ -0,0 +1,98 @@
+package audio
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pcm は指定したレイアウトのリニアPCMのフォーマットを返します。
+func pcm(sampleRate uint32, channels, bitsPerSample uint16) WavFormat {
+	blockAlign := channels * bitsPerSample / 8
+	return WavFormat{
+		AudioFormat:   WavFormatPCM,
+		Channels:      channels,
+		SampleRate:    sampleRate,
+		ByteRate:      sampleRate * uint32(blockAlign),
+		BlockAlign:    blockAlign,
+		BitsPerSample: bitsPerSample,
+	}
+}
+
+// pcm16 は16bitリニアPCMのフォーマットを返します。
+func pcm16(sampleRate uint32, channels uint16) WavFormat {
+	return pcm(sampleRate, channels, 16)
+}
+
+// testWAV は format で d の長さを持つ WAV データを生成します。無音と区別できるよう、PCMデータは fill で埋めます。
+func testWAV(format WavFormat, d time.Duration, fill byte) []byte {
+	data := bytes.Repeat([]byte{fill}, int(d.Seconds()*float64(format.SampleRate))*int(format.BlockAlign))
+	wav, err := buildCombinedWav(buildFormatHeader(format), data, len(data))
+	if err != nil {
+		panic(err)
+	}
+	return wav
+}
+
+// TestCombineClipsSilence は無音区間が前後の音声と同じフォーマット (サンプリングレート・チャンネル数・ビット深度) の
+// PCMデータとして生成され、タイムライン上で指定した長さを占めることを確認します。
+func TestCombineClipsSilence(t *testing.T) {
+	pcm8 := pcm(16000, 1, 8)
+
+	tests := []struct {
+		name        string
+		input       WavFormat
+		opts        []CombineOption
+		want        WavFormat
+		silenceByte byte
+	}{
+		{name: "24kHz モノラル 16bit", input: pcm16(24000, 1), want: pcm16(24000, 1)},
+		{name: "48kHz ステレオ 16bit", input: pcm16(48000, 2), want: pcm16(48000, 2)},
+		{name: "8bit の無音は 0x80", input: pcm8, want: pcm8, silenceByte: 0x80},
+		{name: "出力フォーマットへの変換", input: pcm16(24000, 1), opts: []CombineOption{WithOutputFormat(48000, 2)}, want: pcm16(48000, 2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clips := []Clip{
+				{WAV: testWAV(tt.input, 100*time.Millisecond, 0x11)},
+				{Silence: 250 * time.Millisecond},
+				{WAV: testWAV(tt.input, 100*time.Millisecond, 0x11)},
+			}
+			combined, timeline, err := CombineClipsWithTimeline(clips, tt.opts...)
+			if err != nil {
+				t.Fatalf("CombineClipsWithTimeline: %v", err)
+			}
+
+			_, format, audioData, err := extractAudioData(combined, -1)
+			if err != nil {
+				t.Fatalf("結合結果の解析: %v", err)
+			}
+			if format != tt.want {
+				t.Errorf("出力フォーマット = %+v, want %+v", format, tt.want)
+			}
+
+			wantTimeline := []Span{
+				{Start: 0, End: 100 * time.Millisecond},
+				{Start: 100 * time.Millisecond, End: 350 * time.Millisecond},
+				{Start: 350 * time.Millisecond, End: 450 * time.Millisecond},
+			}
+			if !reflect.DeepEqual(timeline, wantTimeline) {
+				t.Errorf("timeline = %v, want %v", timeline, wantTimeline)
+			}
+
+			// 無音区間は出力フォーマットのフレーム境界に揃い、無音の値のみで構成される
+			frameBytes := func(d time.Duration) int {
+				return int(d.Seconds()*float64(tt.want.SampleRate)) * int(tt.want.BlockAlign)
+			}
+			silence := audioData[frameBytes(100*time.Millisecond):frameBytes(350*time.Millisecond)]
+			if !bytes.Equal(silence, bytes.Repeat([]byte{tt.silenceByte}, len(silence))) {
+				t.Errorf("無音区間に 0x%02x 以外の値が含まれています", tt.silenceByte)
+			}
+			if len(audioData) != frameBytes(450*time.Millisecond) {
+				t.Errorf("PCMデータ長 = %d, want %d", len(audioData), frameBytes(450*time.Millisecond))
+			}
+		})
+	}
+}
@@ -27,3 +27,15 @@ const (
 	// ファイル結合時に RIFF チャンクサイズを更新するために必要
 	RiffChunkSizeOffset = RiffChunkIDSize // RIFFチャンクサイズが書き込まれるオフセット (4バイト目)
 )
+
+const (
+	// チャンクID
+	FmtChunkID  = "fmt "
+	DataChunkID = "data"
+
+	// fmt チャンク本体の最小サイズ (PCM)
+	FmtChunkMinSize = 16
+
+	// fmt チャンクの AudioFormat (リニアPCM)
+	WavFormatPCM = 1
+)
@@ -0,0 +1,107 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// ----------------------------------------------------------------------
+// 16bit リニアPCM のフォーマット変換
+// ----------------------------------------------------------------------
+
+// ConvertPCM16 は16bitリニアPCMのオーディオデータを from のフォーマットから to のフォーマットに変換します。
+// チャンネル数の変換はモノラルとステレオの相互変換のみ対応し、サンプリングレートは線形補間で変換します。
+func ConvertPCM16(data []byte, from, to WavFormat) ([]byte, error) {
+	if !from.isPCM16() || !to.isPCM16() {
+		return nil, fmt.Errorf("16bitリニアPCM以外の変換には対応していません (%s -> %s)", from, to)
+	}
+	if from.Channels == to.Channels && from.SampleRate == to.SampleRate {
+		return data, nil
+	}
+
+	samples := decodePCM16(data, int(from.Channels))
+	samples, err := ConvertChannels(samples, int(from.Channels), int(to.Channels))
+	if err != nil {
+		return nil, err
+	}
+	samples = Resample(samples, int(to.Channels), int(from.SampleRate), int(to.SampleRate))
+
+	return encodePCM16(samples), nil
+}
+
+// ConvertChannels はインターリーブされたサンプル列のチャンネル数を変換します。
+// モノラルからステレオへは同じ値を複製し、ステレオからモノラルへは左右の平均を取ります。
+func ConvertChannels(samples []int16, from, to int) ([]int16, error) {
+	switch {
+	case from == to:
+		return samples, nil
+	case from == 1 && to == 2:
+		out := make([]int16, len(samples)*2)
+		for i, s := range samples {
+			out[i*2] = s
+			out[i*2+1] = s
+		}
+		return out, nil
+	case from == 2 && to == 1:
+		out := make([]int16, len(samples)/2)
+		for i := range out {
+			out[i] = int16((int32(samples[i*2]) + int32(samples[i*2+1])) / 2)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("チャンネル数 %d から %d への変換には対応していません", from, to)
+	}
+}
+
+// Resample はインターリーブされたサンプル列のサンプリングレートを線形補間で変換します。
+// 音声用途を想定した簡易的な変換のため、ダウンサンプリング時の折り返し雑音は除去しません。
+func Resample(samples []int16, channels int, fromRate, toRate int) []int16 {
+	if fromRate == toRate || channels <= 0 || len(samples) < channels {
+		return samples
+	}
+
+	inFrames := len(samples) / channels
+	outFrames := int(int64(inFrames) * int64(toRate) / int64(fromRate))
+	out := make([]int16, outFrames*channels)
+	step := float64(fromRate) / float64(toRate)
+
+	for i := 0; i < outFrames; i++ {
+		pos := float64(i) * step
+		j := int(pos)
+		frac := pos - float64(j)
+		next := j + 1
+		if next >= inFrames {
+			next = inFrames - 1
+		}
+
+		for c := 0; c < channels; c++ {
+			a := float64(samples[j*channels+c])
+			b := float64(samples[next*channels+c])
+			out[i*channels+c] = int16(math.Round(a + (b-a)*frac))
+		}
+	}
+
+	return out
+}
+
+// decodePCM16 はリトルエンディアンの16bit PCMデータをサンプル列に変換します。
+// 端数のバイト (不完全なフレーム) は切り捨てられます。
+func decodePCM16(data []byte, channels int) []int16 {
+	frameSize := 2 * channels
+	n := (len(data) / frameSize) * channels
+	samples := make([]int16, n)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
+
+// encodePCM16 はサンプル列をリトルエンディアンの16bit PCMデータに変換します。
+func encodePCM16(samples []int16) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(s))
+	}
+	return data
+}
@@ -0,0 +1,119 @@
+package audio
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertChannels(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []int16
+		from, to int
+		want     []int16
+		wantErr  bool
+	}{
+		{name: "同じチャンネル数", samples: []int16{1, 2, 3}, from: 1, to: 1, want: []int16{1, 2, 3}},
+		{name: "モノラルからステレオ", samples: []int16{1, -2}, from: 1, to: 2, want: []int16{1, 1, -2, -2}},
+		{name: "ステレオからモノラル (左右の平均)", samples: []int16{100, 200, -100, -300}, from: 2, to: 1, want: []int16{150, -200}},
+		{name: "ステレオからモノラル (クリップしない)", samples: []int16{32767, 32767, -32768, -32768}, from: 2, to: 1, want: []int16{32767, -32768}},
+		{name: "未対応のチャンネル数", samples: []int16{1, 2, 3}, from: 3, to: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertChannels(tt.samples, tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConvertChannels(%d -> %d) = %v, want error", tt.from, tt.to, got)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertChannels(%v, %d, %d) = %v, %v; want %v", tt.samples, tt.from, tt.to, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResample(t *testing.T) {
+	tests := []struct {
+		name             string
+		samples          []int16
+		channels         int
+		fromRate, toRate int
+		want             []int16
+	}{
+		{name: "同じサンプリングレート", samples: []int16{1, 2}, channels: 1, fromRate: 24000, toRate: 24000, want: []int16{1, 2}},
+		{
+			name:    "アップサンプリング (線形補間)",
+			samples: []int16{0, 100, 200, 300}, channels: 1, fromRate: 24000, toRate: 48000,
+			want: []int16{0, 50, 100, 150, 200, 250, 300, 300},
+		},
+		{
+			name:    "ダウンサンプリング",
+			samples: []int16{0, 100, 200, 300}, channels: 1, fromRate: 48000, toRate: 24000,
+			want: []int16{0, 200},
+		},
+		{
+			name:    "ステレオはチャンネルごとに補間",
+			samples: []int16{0, 10, 100, 110}, channels: 2, fromRate: 24000, toRate: 48000,
+			want: []int16{0, 10, 50, 60, 100, 110, 100, 110},
+		},
+		{name: "1フレームに満たない入力", samples: []int16{1}, channels: 2, fromRate: 24000, toRate: 48000, want: []int16{1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resample(tt.samples, tt.channels, tt.fromRate, tt.toRate)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resample(%v, %d, %d, %d) = %v, want %v", tt.samples, tt.channels, tt.fromRate, tt.toRate, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertPCM16(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []int16
+		from, to WavFormat
+		want     []int16
+		wantErr  bool
+	}{
+		{name: "同じフォーマットは変換しない", samples: []int16{1, -1}, from: pcm16(24000, 1), to: pcm16(24000, 1), want: []int16{1, -1}},
+		{
+			name:    "24kHzモノラルから48kHzステレオ",
+			samples: []int16{0, 100}, from: pcm16(24000, 1), to: pcm16(48000, 2),
+			want: []int16{0, 0, 50, 50, 100, 100, 100, 100},
+		},
+		{
+			name:    "48kHzステレオから24kHzモノラル",
+			samples: []int16{0, 100, 50, 50, 200, 400, 0, 0}, from: pcm16(48000, 2), to: pcm16(24000, 1),
+			want: []int16{50, 300},
+		},
+		{
+			name:    "16bit以外は変換できない",
+			samples: []int16{0}, from: WavFormat{AudioFormat: WavFormatPCM, BitsPerSample: 8}.withLayout(24000, 1), to: pcm16(24000, 1),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ConvertPCM16(encodePCM16(tt.samples), tt.from, tt.to)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ConvertPCM16(%s -> %s) error = nil, want error", tt.from, tt.to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertPCM16(%s -> %s): %v", tt.from, tt.to, err)
+			}
+			if got := decodePCM16(data, int(tt.to.Channels)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertPCM16(%s -> %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
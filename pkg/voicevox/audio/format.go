@@ -0,0 +1,132 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// WAV フォーマット情報
+// ----------------------------------------------------------------------
+
+// WavFormat は WAV ファイルの fmt チャンクから読み取ったフォーマット情報です。
+type WavFormat struct {
+	AudioFormat   uint16 // 1 = リニアPCM
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16 // 1サンプルフレームあたりのバイト数 (Channels * BitsPerSample / 8)
+	BitsPerSample uint16
+}
+
+// parseFmtChunk は fmt チャンクの本体（チャンクヘッダーを除く）を解析します。
+func parseFmtChunk(chunk []byte, index int) (WavFormat, error) {
+	if len(chunk) < FmtChunkMinSize {
+		return WavFormat{}, &ErrInvalidWAVHeader{
+			Index:   index,
+			Details: fmt.Sprintf("fmtチャンクが短すぎます (%dバイト)", len(chunk)),
+		}
+	}
+
+	format := WavFormat{
+		AudioFormat:   binary.LittleEndian.Uint16(chunk[0:2]),
+		Channels:      binary.LittleEndian.Uint16(chunk[2:4]),
+		SampleRate:    binary.LittleEndian.Uint32(chunk[4:8]),
+		ByteRate:      binary.LittleEndian.Uint32(chunk[8:12]),
+		BlockAlign:    binary.LittleEndian.Uint16(chunk[12:14]),
+		BitsPerSample: binary.LittleEndian.Uint16(chunk[14:16]),
+	}
+
+	if format.BlockAlign == 0 || format.SampleRate == 0 {
+		return WavFormat{}, &ErrInvalidWAVHeader{
+			Index:   index,
+			Details: "fmtチャンクのサンプリングレートまたはブロックサイズが0です",
+		}
+	}
+
+	return format, nil
+}
+
+// String はフォーマットを "24000Hz/16bit/mono" の形式で表します。
+func (f WavFormat) String() string {
+	channels := fmt.Sprintf("%dch", f.Channels)
+	switch f.Channels {
+	case 1:
+		channels = "mono"
+	case 2:
+		channels = "stereo"
+	}
+	return fmt.Sprintf("%dHz/%dbit/%s", f.SampleRate, f.BitsPerSample, channels)
+}
+
+// Matches は PCMデータをそのまま連結できる (形式・チャンネル数・サンプリングレート・ビット深度が等しい) 場合に true を返します。
+func (f WavFormat) Matches(other WavFormat) bool {
+	return f.AudioFormat == other.AudioFormat &&
+		f.Channels == other.Channels &&
+		f.SampleRate == other.SampleRate &&
+		f.BitsPerSample == other.BitsPerSample
+}
+
+// isPCM16 は16bitリニアPCMであるかを返します。
+func (f WavFormat) isPCM16() bool {
+	return f.AudioFormat == WavFormatPCM && f.BitsPerSample == 16
+}
+
+// withLayout はサンプリングレートとチャンネル数を置き換えたフォーマットを返します。
+// 0 を指定した項目は元の値を維持し、ByteRate と BlockAlign は再計算されます。
+func (f WavFormat) withLayout(sampleRate uint32, channels uint16) WavFormat {
+	if sampleRate != 0 {
+		f.SampleRate = sampleRate
+	}
+	if channels != 0 {
+		f.Channels = channels
+	}
+	f.BlockAlign = f.Channels * f.BitsPerSample / 8
+	f.ByteRate = f.SampleRate * uint32(f.BlockAlign)
+	return f
+}
+
+// buildFormatHeader は RIFFヘッダーと fmt チャンクからなる、data チャンク直前までのヘッダーを生成します。
+// RIFFチャンクサイズは buildCombinedWav で更新されます。
+func buildFormatHeader(f WavFormat) []byte {
+	header := make([]byte, WavRiffHeaderSize+DataChunkHeaderSize+FmtChunkMinSize)
+	copy(header[0:], "RIFF")
+	copy(header[RiffChunkIDSize+RiffChunkSizeSize:], "WAVE")
+
+	chunk := header[WavRiffHeaderSize:]
+	copy(chunk[0:], FmtChunkID)
+	binary.LittleEndian.PutUint32(chunk[4:8], FmtChunkMinSize)
+	binary.LittleEndian.PutUint16(chunk[8:10], f.AudioFormat)
+	binary.LittleEndian.PutUint16(chunk[10:12], f.Channels)
+	binary.LittleEndian.PutUint32(chunk[12:16], f.SampleRate)
+	binary.LittleEndian.PutUint32(chunk[16:20], f.ByteRate)
+	binary.LittleEndian.PutUint16(chunk[20:22], f.BlockAlign)
+	binary.LittleEndian.PutUint16(chunk[22:24], f.BitsPerSample)
+	return header
+}
+
+// frameOffset はPCMデータのバイト数を、サンプル数に基づく再生時間に変換します。
+func (f WavFormat) frameOffset(byteLen int) time.Duration {
+	frames := int64(byteLen / int(f.BlockAlign))
+	return time.Duration(frames * int64(time.Second) / int64(f.SampleRate))
+}
+
+// silenceData は指定されたフォーマットで duration の長さを持つ無音のPCMデータを生成します。
+func silenceData(format WavFormat, duration time.Duration) []byte {
+	frames := int(duration.Seconds()*float64(format.SampleRate) + 0.5)
+	if frames <= 0 {
+		return nil
+	}
+
+	data := make([]byte, frames*int(format.BlockAlign))
+
+	// 8bit PCM は符号なしのため、無音は 0x80 で表現される
+	if format.BitsPerSample == 8 {
+		for i := range data {
+			data[i] = 0x80
+		}
+	}
+
+	return data
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// ----------------------------------------------------------------------
+// インターフェース
+// ----------------------------------------------------------------------
+
+// Cache は合成済みセグメントのWAVデータを保存するキャッシュのインターフェースです。
+// Engine は /audio_query と /synthesis を呼び出す前に Get を参照し、合成後に Put で保存します。
+type Cache interface {
+	// Get はキーに対応するWAVデータを返します。見つからない場合は ok が false になります。
+	Get(ctx context.Context, key string) (wav []byte, ok bool, err error)
+	// Put はキーに対応するWAVデータを保存します。
+	Put(ctx context.Context, key string, wav []byte) error
+}
+
+// ----------------------------------------------------------------------
+// キャッシュキー
+// ----------------------------------------------------------------------
+
+// KeyParams はキャッシュキーを構成する要素です。
+// 合成結果に影響するすべての値を含める必要があります。
+// UserDict はユーザー辞書の内容から生成したフィンガープリントで、辞書の変更によって読みやアクセントが
+// 変わった場合に、変更前の合成結果がキャッシュから返されることを防ぎます。空の場合はキーに含めません。
+type KeyParams struct {
+	EngineVersion string      `json:"engine_version"`
+	StyleID       int         `json:"style_id"`
+	Text          string      `json:"text"`
+	Prosody       api.Prosody `json:"prosody"`
+	UserDict      string      `json:"user_dict,omitempty"`
+}
+
+// NewKey は KeyParams から内容アドレス型のキャッシュキー (SHA-256 の16進文字列) を生成します。
+func NewKey(params KeyParams) string {
+	// KeyParams は常にエンコード可能なため、エラーは発生しない
+	data, _ := json.Marshal(params)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ----------------------------------------------------------------------
+// 統計情報
+// ----------------------------------------------------------------------
+
+// Stats はキャッシュのヒット/ミスなどの累計値です。
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Puts      int64
+	Evictions int64
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// TestNewKey は同じ KeyParams からは常に同じキーが生成され、合成結果に影響する値が1つでも異なれば
+// 別のキーになることを確認します。
+func TestNewKey(t *testing.T) {
+	speed := 1.2
+	otherSpeed := 1.3
+	base := KeyParams{
+		EngineVersion: "0.14.0",
+		StyleID:       3,
+		Text:          "こんにちは",
+		Prosody:       api.Prosody{SpeedScale: &speed},
+		UserDict:      "dict-a",
+	}
+
+	// ポインタのアドレスではなく値でキーが決まること
+	sameSpeed := 1.2
+	same := base
+	same.Prosody = api.Prosody{SpeedScale: &sameSpeed}
+	if got, want := NewKey(same), NewKey(base); got != want {
+		t.Errorf("同じ値の KeyParams のキーが異なります: %s != %s", got, want)
+	}
+	if got := NewKey(base); len(got) != 64 {
+		t.Errorf("NewKey = %q, want SHA-256 の16進文字列 (64文字)", got)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(p *KeyParams)
+	}{
+		{name: "エンジンのバージョン", modify: func(p *KeyParams) { p.EngineVersion = "0.15.0" }},
+		{name: "Style ID", modify: func(p *KeyParams) { p.StyleID = 2 }},
+		{name: "テキスト", modify: func(p *KeyParams) { p.Text = "こんばんは" }},
+		{name: "プロソディの値", modify: func(p *KeyParams) { p.Prosody = api.Prosody{SpeedScale: &otherSpeed} }},
+		{name: "プロソディの指定の有無", modify: func(p *KeyParams) { p.Prosody = api.Prosody{} }},
+		{name: "ユーザー辞書", modify: func(p *KeyParams) { p.UserDict = "dict-b" }},
+		{name: "ユーザー辞書のフィンガープリントなし", modify: func(p *KeyParams) { p.UserDict = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := base
+			tt.modify(&params)
+			if NewKey(params) == NewKey(base) {
+				t.Errorf("%s を変更してもキーが変わりません", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// ファイルシステム実装
+// ----------------------------------------------------------------------
+
+// FileCache はWAVデータをディレクトリ配下のファイルとして保存する Cache 実装です。
+// キーの先頭2文字をサブディレクトリ名として使用し、1ディレクトリあたりのファイル数を抑えます。
+// MaxBytes を超えた場合は最終アクセスが古いものから削除し、MaxAge を過ぎたエントリは期限切れとして扱います。
+// 合計サイズはメモリ上で追跡し、ディレクトリの走査は追跡中のサイズが MaxBytes を超えた場合にのみ行います。
+type FileCache struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+
+	mu        sync.Mutex // 退避処理と合計サイズの排他制御
+	size      int64      // 追跡中の合計サイズ (バイト)。Prune の走査結果で補正する
+	sizeKnown bool       // 一度でも走査して size を初期化したか
+	hits      atomic.Int64
+	misses    atomic.Int64
+	puts      atomic.Int64
+	evictions atomic.Int64
+}
+
+// FileCacheOption は FileCache の設定を行うための関数型です。
+type FileCacheOption func(*FileCache)
+
+// WithMaxBytes はキャッシュ全体の最大サイズ (バイト) を設定します。0 以下の場合は無制限です。
+func WithMaxBytes(n int64) FileCacheOption {
+	return func(c *FileCache) {
+		c.maxBytes = n
+	}
+}
+
+// WithMaxAge はエントリの有効期間を設定します。最終アクセスからこの期間を過ぎたエントリは削除されます。
+// 0 以下の場合は無期限です。
+func WithMaxAge(d time.Duration) FileCacheOption {
+	return func(c *FileCache) {
+		c.maxAge = d
+	}
+}
+
+// NewFileCache は dir をキャッシュディレクトリとする FileCache を作成します。ディレクトリが存在しない場合は作成します。
+func NewFileCache(dir string, opts ...FileCacheOption) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("キャッシュディレクトリの作成に失敗しました (%s): %w", dir, err)
+	}
+
+	c := &FileCache{dir: dir}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Get はキーに対応するWAVデータを読み込みます。期限切れのエントリは削除してミスとして扱います。
+func (c *FileCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	path := c.path(key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		c.misses.Add(1)
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("キャッシュエントリの参照に失敗しました: %w", err)
+	}
+
+	if c.expired(info, time.Now()) {
+		c.misses.Add(1)
+		if c.remove(path) {
+			c.addSize(-info.Size())
+		}
+		return nil, false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.misses.Add(1)
+		return nil, false, fmt.Errorf("キャッシュエントリの読み込みに失敗しました: %w", err)
+	}
+
+	// 最終アクセス時刻を更新し、サイズ超過時の退避順序に反映する
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		slog.DebugContext(ctx, "キャッシュエントリのアクセス時刻の更新に失敗しました", "path", path, "error", err)
+	}
+
+	c.hits.Add(1)
+	return data, true, nil
+}
+
+// Put はWAVデータを一時ファイルに書き込んだ後にリネームし、不完全なエントリが読まれないようにします。
+// 追跡中の合計サイズが MaxBytes を超えた場合 (または未初期化の場合) にのみ Prune を実行します。
+func (c *FileCache) Put(ctx context.Context, key string, wav []byte) error {
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("キャッシュディレクトリの作成に失敗しました: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return fmt.Errorf("キャッシュ一時ファイルの作成に失敗しました: %w", err)
+	}
+	if _, err := tmp.Write(wav); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("キャッシュ一時ファイルへの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("キャッシュ一時ファイルのクローズに失敗しました: %w", err)
+	}
+
+	// 既存のエントリを上書きする場合は、その分を合計サイズから差し引く
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("キャッシュエントリの保存に失敗しました: %w", err)
+	}
+	c.puts.Add(1)
+
+	if c.maxBytes > 0 && c.addSize(int64(len(wav))-replaced) {
+		if err := c.Prune(ctx); err != nil {
+			slog.WarnContext(ctx, "キャッシュの退避処理に失敗しました", "error", err)
+		}
+	}
+	return nil
+}
+
+// Prune は期限切れのエントリを削除し、合計サイズが MaxBytes を超えている場合は
+// 最終アクセスが古いエントリから MaxBytes の pruneTargetRatio 倍まで削除します。
+// 上限の直下まで空けておくことで、Put のたびに走査が発生するのを防ぎます。
+// 走査後の合計サイズは追跡中のサイズとして保持します。
+func (c *FileCache) Prune(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type entry struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+
+	var entries []entry
+	var totalSize int64
+	now := time.Now()
+
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(path) != fileExt {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			// 並行して削除されたエントリは無視する
+			return nil
+		}
+		if c.expired(info, now) {
+			c.remove(path)
+			return nil
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		totalSize += info.Size()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("キャッシュディレクトリの走査に失敗しました: %w", err)
+	}
+	defer func() {
+		c.size = totalSize
+		c.sizeKnown = true
+	}()
+
+	if c.maxBytes <= 0 || totalSize <= c.maxBytes {
+		return nil
+	}
+
+	target := int64(float64(c.maxBytes) * pruneTargetRatio)
+	sort.Slice(entries, func(i, j int) bool { return entries[i].modTime.Before(entries[j].modTime) })
+	for _, e := range entries {
+		if totalSize <= target {
+			break
+		}
+		if c.remove(e.path) {
+			totalSize -= e.size
+		}
+	}
+
+	slog.DebugContext(ctx, "キャッシュの退避処理が完了しました", "total_bytes", totalSize, "max_bytes", c.maxBytes)
+	return nil
+}
+
+// Stats はキャッシュの累計統計を返します。
+func (c *FileCache) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Puts:      c.puts.Load(),
+		Evictions: c.evictions.Load(),
+	}
+}
+
+// ----------------------------------------------------------------------
+// 内部ヘルパー
+// ----------------------------------------------------------------------
+
+const fileExt = ".wav"
+
+// pruneTargetRatio は退避処理で削除した後に残す合計サイズの MaxBytes に対する割合です。
+const pruneTargetRatio = 0.9
+
+// path はキーに対応するファイルパスを返します。
+func (c *FileCache) path(key string) string {
+	prefix := key
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+	return filepath.Join(c.dir, prefix, key+fileExt)
+}
+
+// expired はエントリが有効期間を過ぎているかを判定します。
+func (c *FileCache) expired(info fs.FileInfo, now time.Time) bool {
+	return c.maxAge > 0 && now.Sub(info.ModTime()) > c.maxAge
+}
+
+// remove はエントリを削除し、退避数を加算します。削除できた場合は true を返します。
+func (c *FileCache) remove(path string) bool {
+	if err := os.Remove(path); err != nil {
+		return false
+	}
+	c.evictions.Add(1)
+	return true
+}
+
+// addSize は追跡中の合計サイズに delta を加算し、退避処理が必要かどうかを返します。
+// 合計サイズが未初期化の場合は、走査による初期化が必要なため常に true を返します。
+func (c *FileCache) addSize(delta int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.sizeKnown {
+		return true
+	}
+	c.size += delta
+	return c.maxBytes > 0 && c.size > c.maxBytes
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestFileCacheGetPut は Put したWAVデータが Get で読み出せ、上書きと未登録のキーが正しく扱われ、
+// ヒット/ミス統計に反映されることを確認します。
+func TestFileCacheGetPut(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewFileCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	if _, ok, err := c.Get(ctx, "abcd"); ok || err != nil {
+		t.Fatalf("未登録のキーの Get = ok %v, err %v; want ミス", ok, err)
+	}
+	for _, wav := range [][]byte{[]byte("RIFF-first"), []byte("RIFF-second")} {
+		if err := c.Put(ctx, "abcd", wav); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+		got, ok, err := c.Get(ctx, "abcd")
+		if err != nil || !ok || !bytes.Equal(got, wav) {
+			t.Errorf("Get = %q, %v, %v; want %q", got, ok, err, wav)
+		}
+	}
+
+	want := Stats{Hits: 2, Misses: 1, Puts: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats = %+v, want %+v", got, want)
+	}
+}
+
+// TestFileCacheMaxAge は最終アクセスから MaxAge を過ぎたエントリが、Get ではミスとして削除され、
+// Prune では走査時に削除されることを確認します。
+func TestFileCacheMaxAge(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewFileCache(t.TempDir(), WithMaxAge(time.Hour))
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, key := range []string{"aa01", "aa02", "aa03"} {
+		if err := c.Put(ctx, key, []byte("RIFF")); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+	}
+	for _, key := range []string{"aa01", "aa02"} {
+		if err := os.Chtimes(c.path(key), old, old); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+
+	if _, ok, err := c.Get(ctx, "aa01"); ok || err != nil {
+		t.Errorf("期限切れのエントリの Get = ok %v, err %v; want ミス", ok, err)
+	}
+	if err := c.Prune(ctx); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+
+	for key, wantExists := range map[string]bool{"aa01": false, "aa02": false, "aa03": true} {
+		_, err := os.Stat(c.path(key))
+		if exists := err == nil; exists != wantExists {
+			t.Errorf("%s の存在 = %v, want %v", key, exists, wantExists)
+		}
+	}
+	if got := c.Stats().Evictions; got != 2 {
+		t.Errorf("Evictions = %d, want 2", got)
+	}
+	if _, ok, _ := c.Get(ctx, "aa03"); !ok {
+		t.Error("有効期間内のエントリが Get でミスになりました")
+	}
+}
+
+// TestFileCachePrune は合計サイズが MaxBytes を超えた時点で最終アクセスが古いエントリから退避され、
+// 上限を超えない上書きでは退避処理が行われないことを確認します。
+func TestFileCachePrune(t *testing.T) {
+	ctx := context.Background()
+	c, err := NewFileCache(t.TempDir(), WithMaxBytes(1000))
+	if err != nil {
+		t.Fatalf("NewFileCache: %v", err)
+	}
+
+	entry := make([]byte, 300)
+	base := time.Now().Add(-time.Hour)
+	for i, key := range []string{"aa01", "aa02", "aa03"} {
+		if err := c.Put(ctx, key, entry); err != nil {
+			t.Fatalf("Put(%s): %v", key, err)
+		}
+		// 退避順序を固定するため、最終アクセス時刻を1分ずつずらす
+		at := base.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(c.path(key), at, at); err != nil {
+			t.Fatalf("Chtimes: %v", err)
+		}
+	}
+
+	steps := []struct {
+		name          string
+		key           string
+		wantEvictions int64
+		wantMissing   []string
+	}{
+		{name: "上限を超えると最も古いエントリを退避", key: "aa04", wantEvictions: 1, wantMissing: []string{"aa01"}},
+		{name: "同じサイズの上書きでは退避しない", key: "aa04", wantEvictions: 1},
+		{name: "再び上限を超えると次に古いエントリを退避", key: "aa05", wantEvictions: 2, wantMissing: []string{"aa02"}},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			if err := c.Put(ctx, step.key, entry); err != nil {
+				t.Fatalf("Put(%s): %v", step.key, err)
+			}
+			if got := c.Stats().Evictions; got != step.wantEvictions {
+				t.Errorf("Evictions = %d, want %d", got, step.wantEvictions)
+			}
+			for _, key := range step.wantMissing {
+				if _, err := os.Stat(c.path(key)); !os.IsNotExist(err) {
+					t.Errorf("%s が退避されていません (err: %v)", key, err)
+				}
+			}
+			if c.size > c.maxBytes {
+				t.Errorf("追跡中の合計サイズ = %d, want <= %d", c.size, c.maxBytes)
+			}
+		})
+	}
+}
@@ -11,4 +11,21 @@ const (
 	DefaultMaxParallelSegments = 6
 	DefaultSegmentTimeout      = 300 * time.Second
 	DefaultSegmentRateLimit    = 1000 * time.Millisecond
+
+	// EstimatedDurationPerChar は失敗したセグメントを無音で置き換える際の、1文字あたりの推定発話時間です。
+	EstimatedDurationPerChar = 150 * time.Millisecond
+
+	// MaxTagSuggestions は未定義のタグに対して提示する候補の最大数です。
+	MaxTagSuggestions = 3
+
+	// スナップショットから開始した場合の、エンジンからの話者データ再取得の間隔と最大試行回数
+	SnapshotRefreshRetryInterval = 10 * time.Second
+	SnapshotRefreshMaxAttempts   = 30
+
+	// セグメント単位の再試行 (RetryPolicy) の既定の待機間隔。失敗するたびに DefaultRetryMultiplier 倍し、DefaultRetryMaxBackoff で頭打ちにします。
+	DefaultRetryInitialBackoff = 1 * time.Second
+	DefaultRetryMaxBackoff     = 10 * time.Second
+	DefaultRetryMultiplier     = 2.0
+	// DefaultRetryJitter は待機間隔に加える揺らぎの割合です (0.2 の場合は ±20%)。
+	DefaultRetryJitter = 0.2
 )
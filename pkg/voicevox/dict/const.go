@@ -0,0 +1,25 @@
+package dict
+
+import "github.com/shouni/go-voicevox/pkg/voicevox/api"
+
+// ----------------------------------------------------------------------
+// 辞書の既定値
+// ----------------------------------------------------------------------
+
+const (
+	// DefaultWordType と DefaultPriority は、VOICEVOXエンジンが省略時に使用する値と同じです。
+	DefaultWordType = api.WordTypeProperNoun
+	DefaultPriority = 5
+
+	MinPriority = 0
+	MaxPriority = 10
+)
+
+// partOfSpeech は word_type と、/user_dict が返す品詞 (part_of_speech, part_of_speech_detail_1) の対応です。
+var partOfSpeech = map[api.WordType][2]string{
+	api.WordTypeProperNoun: {"名詞", "固有名詞"},
+	api.WordTypeCommonNoun: {"名詞", "一般"},
+	api.WordTypeVerb:       {"動詞", "自立"},
+	api.WordTypeAdjective:  {"形容詞", "自立"},
+	api.WordTypeSuffix:     {"名詞", "接尾"},
+}
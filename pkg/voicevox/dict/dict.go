@@ -0,0 +1,202 @@
+package dict
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// ----------------------------------------------------------------------
+// 宣言的な辞書定義
+// ----------------------------------------------------------------------
+
+// Entry は辞書ファイルに記述された1単語の定義です。
+type Entry struct {
+	Surface       string       `json:"surface" yaml:"surface"`
+	Pronunciation string       `json:"pronunciation" yaml:"pronunciation"`
+	AccentType    int          `json:"accent_type" yaml:"accent_type"`
+	WordType      api.WordType `json:"word_type,omitempty" yaml:"word_type,omitempty"`
+	Priority      *int         `json:"priority,omitempty" yaml:"priority,omitempty"`
+}
+
+// file はYAML/JSON形式の辞書ファイルのトップレベル構造です。
+// トップレベルが配列の場合は words のみが記述されたものとして扱います。
+type file struct {
+	Words []Entry `json:"words" yaml:"words"`
+}
+
+// LoadFile は辞書ファイルを読み込みます。形式は拡張子 (.yaml/.yml, .json, .csv) から判定されます。
+func LoadFile(path string) ([]Entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("辞書ファイルの読み込みに失敗しました (%s): %w", path, err)
+	}
+
+	var entries []Entry
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		entries, err = parseYAML(data)
+	case ".json":
+		entries, err = parseJSON(data)
+	case ".csv":
+		entries, err = ParseCSV(bytes.NewReader(data))
+	default:
+		return nil, fmt.Errorf("サポートされていない辞書ファイル形式です: %s", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("辞書ファイルの解析に失敗しました (%s): %w", path, err)
+	}
+
+	if err := Validate(entries); err != nil {
+		return nil, fmt.Errorf("辞書ファイルの内容が不正です (%s): %w", path, err)
+	}
+	return entries, nil
+}
+
+func parseYAML(data []byte) ([]Entry, error) {
+	var list []Entry
+	if err := yaml.Unmarshal(data, &list); err == nil {
+		return list, nil
+	}
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f.Words, nil
+}
+
+func parseJSON(data []byte) ([]Entry, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var list []Entry
+		err := json.Unmarshal(trimmed, &list)
+		return list, err
+	}
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, err
+	}
+	return f.Words, nil
+}
+
+// ParseCSV は "surface,pronunciation,accent_type,word_type,priority" の列を持つCSVを解析します。
+// 1行目がヘッダーの場合は列名から列の位置を決定し、word_type と priority の列は省略できます。
+func ParseCSV(r io.Reader) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{"surface": 0, "pronunciation": 1, "accent_type": 2, "word_type": 3, "priority": 4}
+	if strings.EqualFold(strings.TrimSpace(records[0][0]), "surface") {
+		columns = make(map[string]int, len(records[0]))
+		for i, name := range records[0] {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		records = records[1:]
+	}
+
+	field := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	entries := make([]Entry, 0, len(records))
+	for lineIndex, record := range records {
+		entry := Entry{
+			Surface:       field(record, "surface"),
+			Pronunciation: field(record, "pronunciation"),
+			WordType:      api.WordType(field(record, "word_type")),
+		}
+
+		if v := field(record, "accent_type"); v != "" {
+			if entry.AccentType, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("%d 件目の accent_type が数値ではありません: %q", lineIndex+1, v)
+			}
+		}
+		if v := field(record, "priority"); v != "" {
+			priority, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("%d 件目の priority が数値ではありません: %q", lineIndex+1, v)
+			}
+			entry.Priority = &priority
+		}
+
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Validate は必須項目の有無、値の範囲、表層形の重複を検証します。
+func Validate(entries []Entry) error {
+	seen := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		if entry.Surface == "" || entry.Pronunciation == "" {
+			return fmt.Errorf("%d 件目: surface と pronunciation は必須です", i+1)
+		}
+		if entry.AccentType < 0 {
+			return fmt.Errorf("%d 件目 (%s): accent_type は0以上である必要があります", i+1, entry.Surface)
+		}
+		if entry.Priority != nil && (*entry.Priority < MinPriority || *entry.Priority > MaxPriority) {
+			return fmt.Errorf("%d 件目 (%s): priority は %d〜%d の範囲で指定してください", i+1, entry.Surface, MinPriority, MaxPriority)
+		}
+		if _, ok := partOfSpeech[entry.wordType()]; !ok {
+			return fmt.Errorf("%d 件目 (%s): 不明な word_type です: %s", i+1, entry.Surface, entry.WordType)
+		}
+
+		surface := NormalizeSurface(entry.Surface)
+		if prev, dup := seen[surface]; dup {
+			return fmt.Errorf("%d 件目と %d 件目の surface (%s) が重複しています", prev+1, i+1, entry.Surface)
+		}
+		seen[surface] = i
+	}
+	return nil
+}
+
+// wordType は省略時の既定値を補った品詞を返します。
+func (e Entry) wordType() api.WordType {
+	if e.WordType == "" {
+		return DefaultWordType
+	}
+	return e.WordType
+}
+
+// priority は省略時の既定値を補った優先度を返します。
+func (e Entry) priority() int {
+	if e.Priority == nil {
+		return DefaultPriority
+	}
+	return *e.Priority
+}
+
+// input は Entry を /user_dict_word のパラメータに変換します。
+func (e Entry) input() api.WordInput {
+	priority := e.priority()
+	return api.WordInput{
+		Surface:       e.Surface,
+		Pronunciation: e.Pronunciation,
+		AccentType:    e.AccentType,
+		WordType:      e.wordType(),
+		Priority:      &priority,
+	}
+}
@@ -0,0 +1,213 @@
+package dict
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// ----------------------------------------------------------------------
+// エンジン辞書との同期
+// ----------------------------------------------------------------------
+
+// Client は同期に必要なユーザー辞書APIです (api.Client が実装します)。
+type Client interface {
+	GetUserDict(ctx context.Context) (api.UserDict, error)
+	AddUserDictWord(ctx context.Context, word api.WordInput) (string, error)
+	UpdateUserDictWord(ctx context.Context, wordUUID string, word api.WordInput) error
+	DeleteUserDictWord(ctx context.Context, wordUUID string) error
+}
+
+// Report は同期によって変更された (dry run の場合は変更される) 単語の表層形の一覧です。
+type Report struct {
+	Added     []string
+	Updated   []string
+	Deleted   []string
+	Unchanged int
+}
+
+// Changed は辞書に変更があった場合に true を返します。
+func (r *Report) Changed() bool {
+	return len(r.Added)+len(r.Updated)+len(r.Deleted) > 0
+}
+
+// String はログ出力用の要約を返します。
+func (r *Report) String() string {
+	return fmt.Sprintf("追加 %d 件, 更新 %d 件, 削除 %d 件, 変更なし %d 件",
+		len(r.Added), len(r.Updated), len(r.Deleted), r.Unchanged)
+}
+
+// SyncOption は Sync の動作を設定するオプションです。
+type SyncOption func(*syncConfig)
+
+type syncConfig struct {
+	prune  bool
+	dryRun bool
+}
+
+// WithPrune は辞書ファイルに定義されていない (管理対象外の) 単語をエンジンから削除します。
+func WithPrune(prune bool) SyncOption {
+	return func(c *syncConfig) {
+		c.prune = prune
+	}
+}
+
+// WithDryRun はエンジンを変更せず、差分のみを Report として返します。
+func WithDryRun() SyncOption {
+	return func(c *syncConfig) {
+		c.dryRun = true
+	}
+}
+
+// Sync はエンジンの /user_dict と entries の差分を取り、不足している単語の追加と、内容が異なる単語の更新を行います。
+// 単語は表層形 (全角に正規化) で照合されます。
+func Sync(ctx context.Context, client Client, entries []Entry, opts ...SyncOption) (*Report, error) {
+	cfg := &syncConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := Validate(entries); err != nil {
+		return nil, err
+	}
+
+	current, err := client.GetUserDict(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("エンジンのユーザー辞書の取得に失敗しました: %w", err)
+	}
+
+	// 表層形ごとにエンジン側の単語を分類 (UUID順で安定させる)
+	uuids := make([]string, 0, len(current))
+	for wordUUID := range current {
+		uuids = append(uuids, wordUUID)
+	}
+	sort.Strings(uuids)
+	bySurface := make(map[string][]string, len(current))
+	for _, wordUUID := range uuids {
+		surface := NormalizeSurface(current[wordUUID].Surface)
+		bySurface[surface] = append(bySurface[surface], wordUUID)
+	}
+
+	report := &Report{}
+	managed := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		surface := NormalizeSurface(entry.Surface)
+		existing := bySurface[surface]
+
+		if len(existing) == 0 {
+			report.Added = append(report.Added, entry.Surface)
+			if !cfg.dryRun {
+				if _, err := client.AddUserDictWord(ctx, entry.input()); err != nil {
+					return report, fmt.Errorf("単語 %s の追加に失敗しました: %w", entry.Surface, err)
+				}
+			}
+			continue
+		}
+
+		// 同じ表層形の単語が複数ある場合は先頭を管理対象とし、残りは prune の対象とする
+		wordUUID := existing[0]
+		managed[wordUUID] = true
+		if entry.matches(current[wordUUID]) {
+			report.Unchanged++
+			continue
+		}
+
+		report.Updated = append(report.Updated, entry.Surface)
+		if !cfg.dryRun {
+			if err := client.UpdateUserDictWord(ctx, wordUUID, entry.input()); err != nil {
+				return report, fmt.Errorf("単語 %s の更新に失敗しました: %w", entry.Surface, err)
+			}
+		}
+	}
+
+	if cfg.prune {
+		for _, wordUUID := range uuids {
+			if managed[wordUUID] {
+				continue
+			}
+			report.Deleted = append(report.Deleted, current[wordUUID].Surface)
+			if !cfg.dryRun {
+				if err := client.DeleteUserDictWord(ctx, wordUUID); err != nil {
+					return report, fmt.Errorf("単語 %s の削除に失敗しました: %w", current[wordUUID].Surface, err)
+				}
+			}
+		}
+	}
+
+	slog.InfoContext(ctx, "ユーザー辞書を同期しました。",
+		"added", len(report.Added), "updated", len(report.Updated),
+		"deleted", len(report.Deleted), "unchanged", report.Unchanged, "dry_run", cfg.dryRun)
+
+	return report, nil
+}
+
+// SyncFile は辞書ファイルを読み込み、Sync を実行します。
+func SyncFile(ctx context.Context, client Client, path string, opts ...SyncOption) (*Report, error) {
+	entries, err := LoadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Sync(ctx, client, entries, opts...)
+}
+
+// ----------------------------------------------------------------------
+// 辞書のフィンガープリント
+// ----------------------------------------------------------------------
+
+// Fingerprint はユーザー辞書の内容から SHA-256 の16進文字列を生成します。
+// 単語のUUIDは含めず、単語の内容のみを順序に依存しない形で比較するため、同じ単語を登録し直しても値は変わりません。
+// セグメントキャッシュのキー (cache.KeyParams.UserDict) に使用します。
+func Fingerprint(userDict api.UserDict) string {
+	words := make([]string, 0, len(userDict))
+	for _, word := range userDict {
+		// UserDictWord は常にエンコード可能なため、エラーは発生しない
+		data, _ := json.Marshal(word)
+		words = append(words, string(data))
+	}
+	sort.Strings(words)
+
+	sum := sha256.Sum256([]byte(strings.Join(words, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// FetchFingerprint はエンジンの /user_dict を取得し、Fingerprint を返します。
+func FetchFingerprint(ctx context.Context, client Client) (string, error) {
+	userDict, err := client.GetUserDict(ctx)
+	if err != nil {
+		return "", fmt.Errorf("エンジンのユーザー辞書の取得に失敗しました: %w", err)
+	}
+	return Fingerprint(userDict), nil
+}
+
+// matches はエンジンに登録済みの単語が定義と一致するかを返します。
+func (e Entry) matches(word api.UserDictWord) bool {
+	pos := partOfSpeech[e.wordType()]
+	return word.Pronunciation == e.Pronunciation &&
+		word.AccentType == e.AccentType &&
+		word.Priority == e.priority() &&
+		word.PartOfSpeech == pos[0] &&
+		word.PartOfSpeechDetail1 == pos[1]
+}
+
+// NormalizeSurface は表層形をエンジンの登録形式に合わせ、ASCII文字と空白を全角に変換します。
+// VOICEVOXエンジンは単語の登録時に表層形を全角に変換するため、照合にはこの形式を使用します。
+func NormalizeSurface(surface string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ':
+			return '　'
+		case r >= '!' && r <= '~':
+			return r + 0xFEE0
+		default:
+			return r
+		}
+	}, strings.TrimSpace(surface))
+}
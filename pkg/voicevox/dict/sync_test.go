@@ -0,0 +1,155 @@
+package dict
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// fakeClient はメモリ上のユーザー辞書を返し、変更系APIの呼び出しを記録する Client です。
+type fakeClient struct {
+	dict  api.UserDict
+	calls []string
+}
+
+func (c *fakeClient) GetUserDict(ctx context.Context) (api.UserDict, error) {
+	return c.dict, nil
+}
+
+func (c *fakeClient) AddUserDictWord(ctx context.Context, word api.WordInput) (string, error) {
+	c.calls = append(c.calls, "add:"+word.Surface)
+	return "new-uuid", nil
+}
+
+func (c *fakeClient) UpdateUserDictWord(ctx context.Context, wordUUID string, word api.WordInput) error {
+	c.calls = append(c.calls, "update:"+wordUUID)
+	return nil
+}
+
+func (c *fakeClient) DeleteUserDictWord(ctx context.Context, wordUUID string) error {
+	c.calls = append(c.calls, "delete:"+wordUUID)
+	return nil
+}
+
+// properNoun はエンジンに登録済みの固有名詞 (優先度は既定値) を返します。
+func properNoun(surface, pronunciation string, accentType int) api.UserDictWord {
+	return api.UserDictWord{
+		Surface:             surface,
+		Pronunciation:       pronunciation,
+		AccentType:          accentType,
+		Priority:            DefaultPriority,
+		PartOfSpeech:        "名詞",
+		PartOfSpeechDetail1: "固有名詞",
+	}
+}
+
+func TestSync(t *testing.T) {
+	// エンジンは表層形を全角で保持するため、"VOICEVOX" は "ＶＯＩＣＥＶＯＸ" と照合される
+	engineDict := api.UserDict{
+		"uuid-1": properNoun("ＶＯＩＣＥＶＯＸ", "ボイスボックス", 5),
+		"uuid-2": properNoun("ずんだ餅", "ズンダモチ", 3),
+		"uuid-3": properNoun("古い単語", "フルイタンゴ", 1),
+		"uuid-4": properNoun("ずんだ餅", "ズンダモチ", 3), // 同じ表層形の重複
+	}
+	entries := []Entry{
+		{Surface: "VOICEVOX", Pronunciation: "ボイスボックス", AccentType: 5},
+		{Surface: "ずんだ餅", Pronunciation: "ズンダモチ", AccentType: 1},
+		{Surface: "新語", Pronunciation: "シンゴ", AccentType: 1},
+	}
+
+	tests := []struct {
+		name      string
+		opts      []SyncOption
+		want      Report
+		wantCalls []string
+	}{
+		{
+			name:      "追加と更新のみ",
+			want:      Report{Added: []string{"新語"}, Updated: []string{"ずんだ餅"}, Unchanged: 1},
+			wantCalls: []string{"update:uuid-2", "add:新語"},
+		},
+		{
+			name:      "管理対象外と重複の単語を削除",
+			opts:      []SyncOption{WithPrune(true)},
+			want:      Report{Added: []string{"新語"}, Updated: []string{"ずんだ餅"}, Deleted: []string{"古い単語", "ずんだ餅"}, Unchanged: 1},
+			wantCalls: []string{"update:uuid-2", "add:新語", "delete:uuid-3", "delete:uuid-4"},
+		},
+		{
+			name: "dry run はエンジンを変更しない",
+			opts: []SyncOption{WithPrune(true), WithDryRun()},
+			want: Report{Added: []string{"新語"}, Updated: []string{"ずんだ餅"}, Deleted: []string{"古い単語", "ずんだ餅"}, Unchanged: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{dict: engineDict}
+			report, err := Sync(context.Background(), client, entries, tt.opts...)
+			if err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+			if !reflect.DeepEqual(*report, tt.want) {
+				t.Errorf("Report = %+v, want %+v", *report, tt.want)
+			}
+			if !reflect.DeepEqual(client.calls, tt.wantCalls) {
+				t.Errorf("API呼び出し = %q, want %q", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSyncInvalidEntries(t *testing.T) {
+	client := &fakeClient{dict: api.UserDict{}}
+	entries := []Entry{
+		{Surface: "ずんだ餅", Pronunciation: "ズンダモチ", AccentType: 1},
+		{Surface: "ずんだ餅", Pronunciation: "ズンダモチ", AccentType: 3},
+	}
+
+	if _, err := Sync(context.Background(), client, entries); err == nil {
+		t.Fatal("重複した表層形で Sync が成功しました, want error")
+	}
+	if len(client.calls) > 0 {
+		t.Errorf("検証エラー時にAPIが呼び出されました: %q", client.calls)
+	}
+}
+
+// TestFingerprint はフィンガープリントが単語のUUIDと順序に依存せず、単語の内容の変更で変わることを確認します。
+func TestFingerprint(t *testing.T) {
+	base := api.UserDict{
+		"uuid-1": properNoun("ずんだ餅", "ズンダモチ", 3),
+		"uuid-2": properNoun("ＶＯＩＣＥＶＯＸ", "ボイスボックス", 5),
+	}
+	want := Fingerprint(base)
+
+	reRegistered := api.UserDict{
+		"uuid-9": properNoun("ＶＯＩＣＥＶＯＸ", "ボイスボックス", 5),
+		"uuid-8": properNoun("ずんだ餅", "ズンダモチ", 3),
+	}
+	if got := Fingerprint(reRegistered); got != want {
+		t.Errorf("登録し直した辞書のフィンガープリント = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		name string
+		dict api.UserDict
+	}{
+		{name: "空の辞書", dict: api.UserDict{}},
+		{name: "アクセントの変更", dict: api.UserDict{"uuid-1": properNoun("ずんだ餅", "ズンダモチ", 1), "uuid-2": base["uuid-2"]}},
+		{name: "単語の追加", dict: api.UserDict{"uuid-1": base["uuid-1"], "uuid-2": base["uuid-2"], "uuid-3": properNoun("新語", "シンゴ", 1)}},
+		{name: "単語の削除", dict: api.UserDict{"uuid-1": base["uuid-1"]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Fingerprint(tt.dict) == want {
+				t.Errorf("%s でフィンガープリントが変わりません", tt.name)
+			}
+		})
+	}
+
+	fetched, err := FetchFingerprint(context.Background(), &fakeClient{dict: base})
+	if err != nil || fetched != want {
+		t.Errorf("FetchFingerprint = %s, %v; want %s", fetched, err, want)
+	}
+}
@@ -2,34 +2,62 @@ package voicevox
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"os"
-	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
 	"github.com/shouni/go-voicevox/pkg/voicevox/audio"
+	"github.com/shouni/go-voicevox/pkg/voicevox/cache"
+	"github.com/shouni/go-voicevox/pkg/voicevox/normalize"
 	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+	"github.com/shouni/go-voicevox/pkg/voicevox/rewrite"
 	"github.com/shouni/go-voicevox/pkg/voicevox/speaker"
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
 	"golang.org/x/time/rate"
 )
 
 type Engine struct {
-	client  AudioQueryClient
-	data    DataFinder
+	client AudioQueryClient
+	// data は話者データの再読み込み時に差し替えられるため、Style ID のキャッシュと一緒にアトミックに保持します。
+	data    atomic.Pointer[dataHolder]
 	parser  parser.Parser
 	limiter *rate.Limiter
 	config  EngineConfig
 
-	styleIDCache      map[string]int
-	styleIDCacheMutex sync.RWMutex
+	// loader は Refresh で話者データを再取得するための関数です (WithDataLoader で設定)。
+	loader DataLoader
+	// refreshMutex は Refresh の同時実行を防ぎます。
+	refreshMutex sync.Mutex
+	// userDictFingerprint はセグメントキャッシュのキーに含めるユーザー辞書のフィンガープリント (string) です。
+	userDictFingerprint atomic.Value
+
+	// bgCtx は Engine が開始したバックグラウンド処理 (スナップショットからの再取得、定期更新) のコンテキストです。
+	// Close でキャンセルされます。
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
 }
 
 type EngineConfig struct {
 	MaxParallelSegments int
 	SegmentTimeout      time.Duration
 	SegmentRateLimit    time.Duration
+	// EngineVersion はVOICEVOXエンジンのバージョンです。セグメントキャッシュのキーに含まれます。
+	// 起動時の値であり、Refresh で話者データを差し替えると、DataLoader が返したバージョンに更新されます。
+	EngineVersion string
+	// UserDictFingerprint はユーザー辞書の内容から生成したフィンガープリントです。セグメントキャッシュのキーに含まれます。
+	// NewEngineExecutor は起動時に dict.FetchFingerprint で取得します。実行中にユーザー辞書を変更した場合は SetUserDictFingerprint で更新します。
+	UserDictFingerprint string
+	// RefreshInterval は StartAutoRefresh で話者データを再取得する間隔です。0 の場合は自動更新しません。
+	RefreshInterval time.Duration
+	// Retry は失敗したセグメントを再試行する方針です。ゼロ値の場合は再試行しません。
+	// SegmentTimeout は試行ごとに適用されます。
+	Retry RetryPolicy
 }
 
 // --- 内部データ構造と定数 ---
@@ -37,15 +65,32 @@ type EngineConfig struct {
 // engineSegment は parser.Segment に Engine 処理に必要なフィールドを追加した内部構造体です。
 type engineSegment struct {
 	parser.Segment
-	StyleID int
-	Err     error
+	// SpeechText は置換ルールなどの前処理を適用した、/audio_query に渡す読み上げ用テキストです。
+	// Text は字幕用に元のまま保持されます。
+	SpeechText string
+	StyleID    int
+	// ResolvedProsody はエンジン全体・話者・セグメントの設定を重ね合わせた最終的な上書き設定です。
+	ResolvedProsody api.Prosody
+	Err             *SegmentError
+}
+
+// newError はセグメントの情報を付与した SegmentError を生成します。
+func (seg engineSegment) newError(index int, phase SegmentPhase, err error) *SegmentError {
+	return &SegmentError{
+		Index:      index,
+		SpeakerTag: seg.SpeakerTag,
+		Text:       seg.Text,
+		Phase:      phase,
+		Err:        err,
+	}
 }
 
 // segmentResult は Goルーチンからの結果を格納するための内部構造体です。
 type segmentResult struct {
-	index   int
-	wavData []byte
-	err     error
+	index    int
+	wavData  []byte
+	err      *SegmentError
+	cacheHit bool
 }
 
 // ----------------------------------------------------------------------
@@ -55,6 +100,29 @@ type segmentResult struct {
 // ExecuteConfig は Execute メソッドの実行中に適用されるオプション設定を保持する
 type ExecuteConfig struct {
 	FallbackTag string
+	// Prosody はすべてのセグメントに適用されるデフォルトのプロソディ設定です。
+	Prosody api.Prosody
+	// SpeakerProsody は話者タグ (例: "[ずんだもん]") ごとのプロソディ設定です。Prosody より優先されます。
+	SpeakerProsody map[string]api.Prosody
+	// AllowPartial が true の場合、一部のセグメントが失敗しても成功したセグメントの音声を出力します。
+	AllowPartial bool
+	// FillFailedWithSilence が true の場合、失敗したセグメントを推定長の無音で置き換えます (AllowPartial 時のみ有効)。
+	FillFailedWithSilence bool
+	// Cache が設定されている場合、合成済みセグメントを再利用し、API呼び出しを省略します。
+	Cache cache.Cache
+	// SubtitleFormats に指定された形式の字幕ファイルを、WAVファイルと同じ場所・同じベース名で出力します。
+	SubtitleFormats []subtitle.Format
+	// OutputSampleRate と OutputChannels は出力WAVのフォーマットです。0 の場合は最初のセグメントのフォーマットに合わせます。
+	OutputSampleRate uint32
+	OutputChannels   uint16
+	// TextRules は /audio_query に渡す前に読み上げ用テキストへ適用する置換ルールです。
+	TextRules *rewrite.RuleSet
+	// Normalizer は置換ルールの適用後に、数字・日付・単位・英字略語などを読み上げ用の表記に変換します。
+	Normalizer normalize.Normalizer
+	// AutoCorrectTags が true の場合、未定義のタグを編集距離が最も近い登録済みのタグに自動修正します。
+	AutoCorrectTags bool
+	// Progress はセグメントの開始・完了・失敗などの進捗イベントを受け取る関数です。
+	Progress ProgressFunc
 }
 
 // ExecuteOption はオプションを適用するための関数シグネチャ
@@ -63,7 +131,8 @@ type ExecuteOption func(*ExecuteConfig)
 // newExecuteConfig は Execute のデフォルト設定を初期化する
 func newExecuteConfig() *ExecuteConfig {
 	return &ExecuteConfig{
-		FallbackTag: speaker.VvTagNormal,
+		FallbackTag:    speaker.VvTagNormal,
+		SpeakerProsody: make(map[string]api.Prosody),
 	}
 }
 
@@ -76,8 +145,131 @@ func WithFallbackTag(tag string) ExecuteOption {
 	}
 }
 
+// WithProsody は、すべてのセグメントに適用するデフォルトのプロソディ（話速・音高・抑揚・音量）を指定するオプション
+func WithProsody(prosody api.Prosody) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.Prosody = cfg.Prosody.Merge(prosody)
+	}
+}
+
+// WithSpeakerProsody は、話者タグ (例: "[ずんだもん]") ごとのプロソディを指定するオプション
+// スクリプト内のセグメント単位の指定 ({speed=1.2} など) はこれより優先されます。
+func WithSpeakerProsody(speakerTag string, prosody api.Prosody) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.SpeakerProsody[speakerTag] = cfg.SpeakerProsody[speakerTag].Merge(prosody)
+	}
+}
+
+// WithPartialOutput は、一部のセグメントが失敗しても成功したセグメントの音声を書き出すオプション
+// fillWithSilence が true の場合、失敗したセグメントはテキスト長から推定した長さの無音で置き換えられ、
+// 元のスクリプトとのタイミングが維持されます。スキップしたセグメントは ErrPartialSynthesis で報告されます。
+func WithPartialOutput(fillWithSilence bool) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.AllowPartial = true
+		cfg.FillFailedWithSilence = fillWithSilence
+	}
+}
+
+// WithSegmentCache は、合成済みセグメントのキャッシュを指定するオプション
+// キャッシュはエンジンのバージョン、Style ID、テキスト、プロソディをキーとして参照されます。
+func WithSegmentCache(c cache.Cache) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.Cache = c
+	}
+}
+
+// WithSubtitles は、WAVファイルと並べて字幕ファイル (SRT / WebVTT) を出力するオプション
+// 例: 出力先が "out/voice.wav" の場合、"out/voice.srt" や "out/voice.vtt" が作成されます。
+func WithSubtitles(formats ...subtitle.Format) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.SubtitleFormats = append(cfg.SubtitleFormats, formats...)
+	}
+}
+
+// WithOutputFormat は、出力WAVのサンプリングレートとチャンネル数を指定するオプション
+// 0 を指定した項目は最初のセグメントの値を使用します。フォーマットの異なるセグメントは結合時に変換されます (16bit PCMのみ)。
+func WithOutputFormat(sampleRate uint32, channels uint16) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.OutputSampleRate = sampleRate
+		cfg.OutputChannels = channels
+	}
+}
+
+// WithTextRules は、読み上げ用テキストに適用する置換ルールを指定するオプション
+// 置換後のテキストは /audio_query にのみ渡され、字幕や Result には元のテキストが使用されます。
+func WithTextRules(rules *rewrite.RuleSet) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.TextRules = rules
+	}
+}
+
+// WithNormalization は、読み上げ用テキストの正規化 (数字・日付・単位・英字略語の変換、絵文字・Markdownの除去) を有効にするオプション
+// normalizers を省略した場合は normalize.Default() が使用されます。正規化は WithTextRules の置換ルールの後に適用されます。
+func WithNormalization(normalizers ...normalize.Normalizer) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		if len(normalizers) == 0 {
+			cfg.Normalizer = normalize.Default()
+			return
+		}
+		cfg.Normalizer = normalize.Chain(normalizers)
+	}
+}
+
+// WithTagAutoCorrect は、未定義の話者・スタイルタグを最も近い登録済みのタグに自動修正するオプション
+// 例: "[ずんだもん][あまーま]" は "[ずんだもん][あまあま]" として合成されます。修正内容は警告ログに出力されます。
+func WithTagAutoCorrect() ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		cfg.AutoCorrectTags = true
+	}
+}
+
+// WithProgress は、音声合成バッチ処理の進捗イベントを受け取る関数を登録するオプション
+// 複数回指定した場合は、登録した順にすべての関数が呼び出されます。
+func WithProgress(fn ProgressFunc) ExecuteOption {
+	return func(cfg *ExecuteConfig) {
+		if fn == nil {
+			return
+		}
+		if prev := cfg.Progress; prev != nil {
+			cfg.Progress = func(ev ProgressEvent) {
+				prev(ev)
+				fn(ev)
+			}
+			return
+		}
+		cfg.Progress = fn
+	}
+}
+
+// WithProgressChannel は、音声合成バッチ処理の進捗イベントをチャネルに送信するオプション
+// 送信はブロックするため、呼び出し元は処理が終わるまでチャネルから受信し続ける必要があります。
+// チャネルは Engine では閉じられません。ProgressBatchComplete を受信した時点でバッチ処理は終了しています。
+func WithProgressChannel(ch chan<- ProgressEvent) ExecuteOption {
+	return WithProgress(func(ev ProgressEvent) {
+		ch <- ev
+	})
+}
+
+// ----------------------------------------------------------------------
+// Engine のオプション定義
+// ----------------------------------------------------------------------
+
+// DataLoader は話者データを (再) 取得する関数です。
+// 話者データとともに、取得元のエンジンのバージョンを返します。バージョンが不明な場合は空文字列を返し、現在の値を維持します。
+type DataLoader func(ctx context.Context) (DataFinder, string, error)
+
+// EngineOption は NewEngine にオプションを適用するための関数シグネチャ
+type EngineOption func(*Engine)
+
+// WithDataLoader は、Refresh で話者データを再取得するためのローダーを指定するオプション
+func WithDataLoader(loader DataLoader) EngineOption {
+	return func(e *Engine) {
+		e.loader = loader
+	}
+}
+
 // NewEngine は新しい Engine インスタンスを作成し、依存関係を注入します。
-func NewEngine(client AudioQueryClient, data DataFinder, p parser.Parser, config EngineConfig) *Engine {
+func NewEngine(client AudioQueryClient, data DataFinder, p parser.Parser, config EngineConfig, opts ...EngineOption) *Engine {
 
 	// NOTE: Default 定数が未定義のため、仮の値を設定
 	if config.MaxParallelSegments == 0 {
@@ -93,14 +285,154 @@ func NewEngine(client AudioQueryClient, data DataFinder, p parser.Parser, config
 	// rate.Every を使用して、指定された間隔でトークンを生成するリミッターを作成
 	limiter := rate.NewLimiter(rate.Every(config.SegmentRateLimit), 1)
 
-	return &Engine{
-		client:       client,
-		data:         data,
-		parser:       p,
-		config:       config,
-		styleIDCache: make(map[string]int),
-		limiter:      limiter,
+	e := &Engine{
+		client:  client,
+		parser:  p,
+		config:  config,
+		limiter: limiter,
+	}
+	e.bgCtx, e.bgCancel = context.WithCancel(context.Background())
+	e.data.Store(newDataHolder(data, config.EngineVersion))
+	e.userDictFingerprint.Store(config.UserDictFingerprint)
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// dataHolder は DataFinder と、そのデータから解決した Style ID のキャッシュをアトミックに差し替えるための入れ物です。
+// キャッシュを話者データと一緒に差し替えることで、差し替え前のデータで始まった検索の結果が、
+// 新しいデータのキャッシュに書き込まれることを防ぎます。
+type dataHolder struct {
+	DataFinder
+	// engineVersion は話者データを取得したエンジンのバージョンです。セグメントキャッシュのキーに使用します。
+	engineVersion string
+
+	styleIDCache      map[string]int
+	styleIDCacheMutex sync.RWMutex
+}
+
+// newDataHolder は空のキャッシュを持つ dataHolder を作成します。
+func newDataHolder(data DataFinder, engineVersion string) *dataHolder {
+	return &dataHolder{DataFinder: data, engineVersion: engineVersion, styleIDCache: make(map[string]int)}
+}
+
+// cachedStyleID はキャッシュからタグの Style ID を返します (読み取り操作)。
+func (h *dataHolder) cachedStyleID(tag string) (int, bool) {
+	h.styleIDCacheMutex.RLock()
+	defer h.styleIDCacheMutex.RUnlock()
+	id, ok := h.styleIDCache[tag]
+	return id, ok
+}
+
+// cacheStyleID はタグに対して解決した Style ID をキャッシュに保存します (書き込み操作)。
+func (h *dataHolder) cacheStyleID(tag string, styleID int) {
+	h.styleIDCacheMutex.Lock()
+	h.styleIDCache[tag] = styleID
+	h.styleIDCacheMutex.Unlock()
+}
+
+// dataFinder は現在の話者データを返します。
+func (e *Engine) dataFinder() DataFinder {
+	return e.data.Load().DataFinder
+}
+
+// swapData は話者データとエンジンのバージョンを差し替えます。古いデータに基づく Style ID のキャッシュは、古いデータと一緒に破棄されます。
+// engineVersion が空の場合は現在のバージョンを維持します。
+func (e *Engine) swapData(data DataFinder, engineVersion string) {
+	if engineVersion == "" {
+		engineVersion = e.data.Load().engineVersion
+	}
+	e.data.Store(newDataHolder(data, engineVersion))
+}
+
+// engineVersion は現在の話者データを取得したエンジンのバージョンを返します。
+func (e *Engine) engineVersion() string {
+	return e.data.Load().engineVersion
+}
+
+// SetUserDictFingerprint はセグメントキャッシュのキーに含めるユーザー辞書のフィンガープリントを更新します。
+// 実行中にユーザー辞書を変更した場合に新しいフィンガープリントを渡すと、変更前の辞書で合成した結果はキャッシュから返されなくなります。
+func (e *Engine) SetUserDictFingerprint(fingerprint string) {
+	e.userDictFingerprint.Store(fingerprint)
+}
+
+// Close は Engine が開始したバックグラウンド処理 (スナップショットからの再取得、StartAutoRefresh による定期更新) を停止します。
+// 実行中の合成処理には影響しません。NewEngineExecutor が返す Executor は、型アサーションで io.Closer を取得できます。
+func (e *Engine) Close() error {
+	e.bgCancel()
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// 話者データの更新
+// ----------------------------------------------------------------------
+
+// Refresh は DataLoader で話者データとエンジンのバージョンを再取得し、アトミックに差し替えて Style ID のキャッシュを破棄します。
+// 音声ライブラリの追加やエンジンの再起動で Style ID が変わった場合に、サービスを再起動せずに反映できます。
+// 実行中の合成処理は、差し替え前に解決した Style ID のまま完了します。
+func (e *Engine) Refresh(ctx context.Context) error {
+	if e.loader == nil {
+		return fmt.Errorf("話者データのローダーが設定されていません (WithDataLoader を指定してください)")
+	}
+
+	e.refreshMutex.Lock()
+	defer e.refreshMutex.Unlock()
+
+	data, engineVersion, err := e.loader(ctx)
+	if err != nil {
+		return fmt.Errorf("話者データの再取得に失敗しました: %w", err)
+	}
+
+	e.swapData(data, engineVersion)
+	slog.InfoContext(ctx, "話者データを更新し、Style IDキャッシュを破棄しました。", "engine_version", e.engineVersion())
+	return nil
+}
+
+// StartAutoRefresh は EngineConfig.RefreshInterval ごとに Refresh を実行するGoルーチンを開始します。
+// ctx がキャンセルされるか Close を呼び出すと停止します。RefreshInterval が 0 以下の場合は何もしません。
+func (e *Engine) StartAutoRefresh(ctx context.Context) {
+	interval := e.config.RefreshInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-e.bgCtx.Done():
+				return
+			case <-ticker.C:
+				if err := e.Refresh(ctx); err != nil {
+					slog.WarnContext(ctx, "話者データの定期更新に失敗しました。現在のデータで継続します。", "error", err)
+				}
+			}
+		}
+	}()
+	slog.InfoContext(ctx, "話者データの定期更新を開始しました。", "interval", interval.String())
+}
+
+// Styles は現在登録されている話者・スタイルタグと Style ID の対応を返します。
+// 話者データが TagLister を実装していない場合は nil を返します。
+func (e *Engine) Styles() map[string]int {
+	data := e.dataFinder()
+	lister, ok := data.(TagLister)
+	if !ok {
+		return nil
 	}
+
+	styles := make(map[string]int)
+	for _, tag := range lister.Tags() {
+		if id, ok := data.GetStyleID(tag); ok {
+			styles[tag] = id
+		}
+	}
+	return styles
 }
 
 // ----------------------------------------------------------------------
@@ -108,114 +440,331 @@ func NewEngine(client AudioQueryClient, data DataFinder, p parser.Parser, config
 // ----------------------------------------------------------------------
 
 // getStyleID はセグメントの話者タグから対応するStyle IDを検索し、キャッシュを使用/更新します。
-func (e *Engine) getStyleID(ctx context.Context, tag string, baseSpeakerTag string, index int) (int, error) {
-	// 1. 内部キャッシュのチェック (読み取り操作)
-	e.styleIDCacheMutex.RLock()
-	if id, ok := e.styleIDCache[tag]; ok {
-		e.styleIDCacheMutex.RUnlock()
+// 完全一致しない場合は、話者タグのみの指定に対するデフォルトスタイル、表記ゆれの吸収、
+// (AutoCorrectTags 時は) 近いタグへの自動修正、デフォルトスタイルへのフォールバックの順に試みます。
+// キャッシュに保存するのは ExecuteOption に依存しない結果 (完全一致、話者タグのみの指定、表記ゆれの吸収) のみです。
+func (e *Engine) getStyleID(ctx context.Context, tag string, baseSpeakerTag string, index int, cfg *ExecuteConfig) (int, error) {
+	// 検索中に話者データが差し替えられても一貫した結果になるよう、参照を固定する。
+	// キャッシュも同じ holder に保存するため、差し替え後のキャッシュに古い結果が残ることはない
+	holder := e.data.Load()
+	data := holder.DataFinder
+
+	// 1. 内部キャッシュのチェック
+	if id, ok := holder.cachedStyleID(tag); ok {
 		return id, nil
 	}
-	e.styleIDCacheMutex.RUnlock()
 
 	// 2. 完全なタグでの検索 (キャッシュミスの場合)
-	styleID, ok := e.data.GetStyleID(tag)
+	styleID, ok := data.GetStyleID(tag)
 	if ok {
-		// キャッシュに保存 (書き込み操作)
-		e.styleIDCacheMutex.Lock()
-		e.styleIDCache[tag] = styleID
-		e.styleIDCacheMutex.Unlock()
+		holder.cacheStyleID(tag, styleID)
 		return styleID, nil
 	}
 
-	// 3. フォールバック処理: デフォルトスタイルを試す
+	// 3. スタイルを省略した話者タグのみの指定 ("[ナレーター] テキスト") は、話者のデフォルトスタイルで解決する
+	if tag == baseSpeakerTag {
+		if defaultTag, ok := data.GetDefaultTag(tag); ok {
+			if styleID, ok := data.GetStyleID(defaultTag); ok {
+				holder.cacheStyleID(tag, styleID)
+				return styleID, nil
+			}
+		}
+	}
+
+	// 4. 表記ゆれ (全角括弧、空白、ひらがな/カタカナなど) を吸収した検索
+	var knownTags []string
+	if lister, ok := data.(TagLister); ok {
+		knownTags = lister.Tags()
+	}
+	if resolved, ok := speaker.FindTag(tag, knownTags); ok {
+		if styleID, ok := data.GetStyleID(resolved); ok {
+			slog.DebugContext(ctx, "表記ゆれを吸収してタグを解決しました", "segment_index", index, "original_tag", tag, "resolved_tag", resolved)
+			holder.cacheStyleID(tag, styleID)
+			return styleID, nil
+		}
+	}
+
+	// 5. 近いタグの候補 (自動修正が有効な場合は最も近い候補を使用)
+	suggestions := speaker.SuggestTags(tag, knownTags, MaxTagSuggestions)
+	if cfg.AutoCorrectTags && len(suggestions) > 0 {
+		if styleID, ok := data.GetStyleID(suggestions[0]); ok {
+			// 自動修正の結果は ExecuteOption に依存するため、エンジン全体のキャッシュには保存しない
+			slog.WarnContext(ctx, "未定義のタグを近いタグに自動修正しました",
+				"segment_index", index,
+				"original_tag", tag,
+				"corrected_tag", suggestions[0])
+			return styleID, nil
+		}
+	}
+
+	// 6. フォールバック処理: デフォルトスタイルを試す
 	if baseSpeakerTag == "" {
-		return 0, fmt.Errorf("話者タグ %s の抽出失敗 (セグメント %d)", tag, index)
+		return 0, fmt.Errorf("話者タグ %s の抽出失敗", tag)
 	}
 
-	fallbackKey, defaultOk := e.data.GetDefaultTag(baseSpeakerTag)
+	fallbackKey, defaultOk := data.GetDefaultTag(baseSpeakerTag)
+	if !defaultOk {
+		// 話者タグ自体の表記ゆれを吸収して再検索
+		if speakerTags := baseTags(knownTags); len(speakerTags) > 0 {
+			if resolvedBase, ok := speaker.FindTag(baseSpeakerTag, speakerTags); ok {
+				fallbackKey, defaultOk = data.GetDefaultTag(resolvedBase)
+			}
+		}
+	}
 
 	if defaultOk {
 		slog.WarnContext(ctx, "AI出力タグが未定義のためフォールバック",
 			"segment_index", index,
 			"original_tag", tag,
-			"fallback_key", fallbackKey)
+			"fallback_key", fallbackKey,
+			"did_you_mean", suggestions)
 
 		// デフォルトスタイルキーに対応するIDを検索
-		styleID, styleOk := e.data.GetStyleID(fallbackKey)
+		styleID, styleOk := data.GetStyleID(fallbackKey)
 		if styleOk {
-			// フォールバック成功の場合もキャッシュに保存 (書き込み操作)
-			e.styleIDCacheMutex.Lock()
-			e.styleIDCache[tag] = styleID // 元のタグに対してデフォルトのIDを保存
-			e.styleIDCacheMutex.Unlock()
+			// フォールバックの結果はキャッシュしない。キャッシュすると、同じタグを AutoCorrectTags 付きで
+			// 解決する後続の呼び出しが、自動修正ではなくデフォルトスタイルを返してしまうため
 			return styleID, nil
 		}
 	}
 
-	return 0, fmt.Errorf("話者・スタイルタグ %s (およびデフォルトスタイル) に対応するStyle IDが見つかりません (セグメント %d)", tag, index)
+	return 0, &ErrUnknownStyleTag{Tag: tag, Suggestions: suggestions}
+}
+
+// baseTags は "[話者][スタイル]" 形式のタグから、重複のない話者タグ部分の一覧を返します。
+func baseTags(tags []string) []string {
+	seen := make(map[string]bool)
+	var bases []string
+	for _, tag := range tags {
+		end := strings.Index(tag, "]")
+		if end < 0 {
+			continue
+		}
+		if base := tag[:end+1]; !seen[base] {
+			seen[base] = true
+			bases = append(bases, base)
+		}
+	}
+	return bases
 }
 
 // processSegment は単一のセグメントに対してAPI呼び出しを実行します。
-func (e *Engine) processSegment(ctx context.Context, seg engineSegment, index int) segmentResult {
+// キャッシュが設定されている場合は、API呼び出しの前にキャッシュを参照します。
+// EngineConfig.Retry が設定されている場合は、再試行可能なエラー (IsRetryable) で失敗した合成をやり直します。
+func (e *Engine) processSegment(ctx context.Context, seg engineSegment, index int, cfg *ExecuteConfig, progress *progressReporter) segmentResult {
 	// seg.Err は事前計算で処理されるため、ここでは主にネットワーク処理
 	if seg.Err != nil {
 		return segmentResult{index: index, err: seg.Err}
 	}
+
+	// 0. キャッシュの参照
+	var cacheKey string
+	if cfg.Cache != nil {
+		cacheKey = cache.NewKey(cache.KeyParams{
+			EngineVersion: e.engineVersion(),
+			StyleID:       seg.StyleID,
+			Text:          seg.SpeechText,
+			Prosody:       seg.ResolvedProsody,
+			UserDict:      e.userDictFingerprint.Load().(string),
+		})
+		wavData, ok, err := cfg.Cache.Get(ctx, cacheKey)
+		if err != nil {
+			slog.WarnContext(ctx, "セグメントキャッシュの参照に失敗しました。APIで合成します。", "segment_index", index, "error", err)
+		} else if ok {
+			return segmentResult{index: index, wavData: wavData, cacheHit: true}
+		}
+	}
+
+	// 1〜3. 合成 (再試行ポリシーに従ってクエリと合成の組をやり直す)
+	var wavData []byte
+	for attempt := 1; ; attempt++ {
+		var segErr *SegmentError
+		wavData, segErr = e.synthesizeSegment(ctx, seg, index)
+		if segErr == nil {
+			break
+		}
+
+		segErr.Attempts = attempt
+		policy := e.config.Retry
+		if !policy.enabled() || attempt >= policy.MaxAttempts || ctx.Err() != nil || !IsRetryable(segErr.Err) {
+			return segmentResult{index: index, err: segErr}
+		}
+
+		delay := policy.backoff(attempt)
+		slog.WarnContext(ctx, "セグメントの合成に失敗しました。再試行します。",
+			"segment_index", index,
+			"phase", segErr.Phase,
+			"attempt", attempt,
+			"max_attempts", policy.MaxAttempts,
+			"retry_in", delay.String(),
+			"error", segErr.Err)
+		progress.retried(seg, index, attempt+1, segErr)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return segmentResult{index: index, err: segErr}
+		case <-timer.C:
+		}
+	}
+
+	// 4. キャッシュへの保存 (失敗しても合成結果は利用する)
+	if cfg.Cache != nil {
+		if err := cfg.Cache.Put(ctx, cacheKey, wavData); err != nil {
+			slog.WarnContext(ctx, "セグメントキャッシュへの保存に失敗しました。", "segment_index", index, "error", err)
+		}
+	}
+
+	// 5. 成功
+	return segmentResult{index: index, wavData: wavData}
+}
+
+// synthesizeSegment は1回の試行として /audio_query と /synthesis を呼び出し、WAVデータを検証します。
+// 試行ごとに EngineConfig.SegmentTimeout のタイムアウトを適用します。
+func (e *Engine) synthesizeSegment(ctx context.Context, seg engineSegment, index int) ([]byte, *SegmentError) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.SegmentTimeout)
+	defer cancel()
+
 	styleID := seg.StyleID
 
-	var queryBody []byte
-	var currentErr error
+	// レートリミット待機 (キャッシュヒット時は待機しない)
+	if err := e.limiter.Wait(ctx); err != nil {
+		return nil, seg.newError(index, PhaseRateLimit, err)
+	}
 
 	// 1. RunAudioQuery (インターフェースのメソッド名に合わせる)
-	queryBody, currentErr = e.client.RunAudioQuery(seg.Text, styleID, ctx)
-	if currentErr != nil {
-		return segmentResult{index: index, err: fmt.Errorf("セグメント %d のオーディオクエリ失敗: %w", index, currentErr)}
+	queryBody, err := e.client.RunAudioQuery(seg.SpeechText, styleID, ctx)
+	if err != nil {
+		return nil, seg.newError(index, PhaseAudioQuery, err)
+	}
+
+	// プロソディの上書き (設定がある場合のみクエリをデコードして書き換える)
+	if !seg.ResolvedProsody.IsZero() {
+		queryBody, err = applyProsody(queryBody, seg.ResolvedProsody)
+		if err != nil {
+			return nil, seg.newError(index, PhaseProsody, err)
+		}
 	}
 
 	// 2. RunSynthesis (インターフェースのメソッド名に合わせる)
-	wavData, currentErr := e.client.RunSynthesis(queryBody, styleID, ctx)
-	if currentErr != nil {
-		return segmentResult{index: index, err: fmt.Errorf("セグメント %d の音声合成失敗: %w", index, currentErr)}
+	wavData, err := e.client.RunSynthesis(queryBody, styleID, ctx)
+	if err != nil {
+		return nil, seg.newError(index, PhaseSynthesis, err)
 	}
 
-	// 3. 成功
-	return segmentResult{index: index, wavData: wavData}
+	// 3. WAVヘッダーの検証 (結合時ではなくセグメント単位でエラーを特定するため)
+	if _, err = audio.ValidateWavData(wavData); err != nil {
+		return nil, seg.newError(index, PhaseWavValidation, err)
+	}
+
+	return wavData, nil
+}
+
+// applyProsody はクエリJSONをデコードしてプロソディを反映し、再エンコードします。
+func applyProsody(queryBody []byte, prosody api.Prosody) ([]byte, error) {
+	query, err := api.DecodeAudioQuery(queryBody)
+	if err != nil {
+		return nil, err
+	}
+	query.ApplyProsody(prosody)
+	return query.Encode()
 }
 
 // ----------------------------------------------------------------------
 // メイン処理 (Execute メソッド)
 // ----------------------------------------------------------------------
 
+// Execute はスクリプトを実行し、結合したWAVファイル (および指定された字幕ファイル) を outputWavFile に書き出します。
+// 処理本体は Synthesize に委譲し、このメソッドはファイルへの書き込みのみを担当します。
 func (e *Engine) Execute(ctx context.Context, scriptContent string, outputWavFile string, opts ...ExecuteOption) error {
-	// 1. 設定初期化と適用
 	cfg := newExecuteConfig()
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
-	// 2. スクリプト解析とセグメントの事前準備
+	result, err := e.synthesize(ctx, scriptContent, cfg)
+	var partialErr *ErrPartialSynthesis
+	if err != nil && !errors.As(err, &partialErr) {
+		return err
+	}
+
+	// ファイルへの書き込み
+	if partialErr != nil {
+		slog.WarnContext(ctx, "一部のセグメントを除いて合成と結合が完了しました。部分的な結果のファイル書き込みを行います。",
+			"output_file", outputWavFile,
+			"skipped_indices", partialErr.SkippedIndices,
+			"filled_with_silence", partialErr.FilledWithSilence)
+	} else {
+		slog.InfoContext(ctx, "全てのセグメントの合成と結合が完了しました。ファイル書き込みを行います。", "output_file", outputWavFile)
+	}
+	if err := writeOutputFiles(outputWavFile, result, cfg.SubtitleFormats); err != nil {
+		return err
+	}
+
+	if partialErr != nil {
+		partialErr.OutputFile = outputWavFile
+		return partialErr
+	}
+	return nil
+}
+
+// Synthesize はスクリプトを実行し、結合したWAVデータとセグメントのメタデータをメモリ上で返します。
+// WithPartialOutput 指定時に一部のセグメントが失敗した場合は、Result とともに ErrPartialSynthesis を返します。
+func (e *Engine) Synthesize(ctx context.Context, scriptContent string, opts ...ExecuteOption) (*Result, error) {
+	cfg := newExecuteConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return e.synthesize(ctx, scriptContent, cfg)
+}
+
+// SynthesizeTo は Synthesize の結果のWAVデータを w に書き込みます。
+// HTTPレスポンスやオブジェクトストレージへのストリーミングに利用できます。
+func (e *Engine) SynthesizeTo(ctx context.Context, w io.Writer, scriptContent string, opts ...ExecuteOption) (*Result, error) {
+	result, err := e.Synthesize(ctx, scriptContent, opts...)
+	var partialErr *ErrPartialSynthesis
+	if err != nil && !errors.As(err, &partialErr) {
+		return nil, err
+	}
+
+	if _, writeErr := w.Write(result.WAV); writeErr != nil {
+		return nil, fmt.Errorf("WAVデータの書き込みに失敗しました: %w", writeErr)
+	}
+	return result, err
+}
+
+// synthesize は Execute / Synthesize の共通処理です。
+func (e *Engine) synthesize(ctx context.Context, scriptContent string, cfg *ExecuteConfig) (*Result, error) {
+	// 1. スクリプト解析とセグメントの事前準備
 	segments, preCalcErrors, err := e.prepareSegments(ctx, scriptContent, cfg)
 	if err != nil {
 		// fatal error (e.g., parsing failed, or all segments failed pre-calc)
-		return err
+		return nil, err
 	}
 
-	// 3. 音声合成バッチ処理の実行
-	orderedAudioDataList, runtimeErrors := e.runSynthesisBatch(ctx, segments)
+	// 2. 音声合成バッチ処理の実行
+	results, runtimeErrors := e.runSynthesisBatch(ctx, segments, cfg)
+
+	// キャンセルされた場合は未処理のセグメントが残るため、出力は行わない
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("音声合成処理が中断されました: %w", err)
+	}
 
-	// 4. 結果の集約とファイルへの書き込み
-	return e.finalizeOutput(ctx, orderedAudioDataList, outputWavFile, preCalcErrors, runtimeErrors)
+	// 3. 結果の集約とWAVデータの結合
+	return e.assembleResult(ctx, cfg, segments, results, preCalcErrors, runtimeErrors)
 }
 
 // prepareSegments はスクリプトを解析し、Style IDを決定するなど、並列処理の前のすべての準備を行います。
-func (e *Engine) prepareSegments(ctx context.Context, scriptContent string, cfg *ExecuteConfig) ([]engineSegment, []string, error) {
+func (e *Engine) prepareSegments(ctx context.Context, scriptContent string, cfg *ExecuteConfig) ([]engineSegment, []*SegmentError, error) {
 	// スクリプト解析
 	parserSegments, err := e.parser.Parse(scriptContent, cfg.FallbackTag)
 	if err != nil {
-		return nil, nil, fmt.Errorf("スクリプトの解析に失敗しました: %w", err)
+		return nil, nil, &ErrInvalidScript{Details: "スクリプトの解析に失敗しました", WrappedErr: err}
 	}
 
 	if len(parserSegments) == 0 {
-		return nil, nil, fmt.Errorf("スクリプトから有効なセグメントを抽出できませんでした。AIの出力形式を確認してください")
+		return nil, nil, &ErrInvalidScript{Details: "スクリプトから有効なセグメントを抽出できませんでした。AIの出力形式を確認してください"}
 	}
 
 	// Engine内部構造体への変換と事前計算
@@ -224,33 +773,65 @@ func (e *Engine) prepareSegments(ctx context.Context, scriptContent string, cfg
 		segments[i] = engineSegment{Segment: pSeg}
 	}
 
-	var preCalcErrors []string
+	var preCalcErrors []*SegmentError
+	speechCount := 0
 	for i := range segments {
 		seg := &segments[i] // ポインターでアクセス
 
+		// 無音セグメントはAPIを呼び出さないため、Style IDの決定は不要
+		if seg.IsSilence() {
+			continue
+		}
+		speechCount++
+
 		// Style IDの決定
-		styleID, err := e.getStyleID(ctx, seg.SpeakerTag, seg.BaseSpeakerTag, i)
+		styleID, err := e.getStyleID(ctx, seg.SpeakerTag, seg.BaseSpeakerTag, i, cfg)
 		if err != nil {
-			seg.Err = err
-			preCalcErrors = append(preCalcErrors, err.Error())
+			seg.Err = seg.newError(i, PhaseStyleLookup, err)
+			preCalcErrors = append(preCalcErrors, seg.Err)
 		} else {
 			seg.StyleID = styleID
 		}
-	}
 
-	if len(preCalcErrors) == len(segments) {
-		return nil, nil, &ErrSynthesisBatch{
-			TotalErrors: len(preCalcErrors),
-			Details:     preCalcErrors,
+		// 読み上げ用テキストの前処理
+		if err := e.prepareSpeechText(seg, cfg); err != nil && seg.Err == nil {
+			seg.Err = seg.newError(i, PhaseTextPreprocess, err)
+			preCalcErrors = append(preCalcErrors, seg.Err)
 		}
+
+		// プロソディの決定 (エンジン全体 < 話者 < セグメント の順に優先)
+		seg.ResolvedProsody = cfg.Prosody.
+			Merge(cfg.SpeakerProsody[seg.BaseSpeakerTag]).
+			Merge(api.Prosody(seg.Prosody))
+	}
+
+	if speechCount == 0 {
+		return nil, nil, &ErrInvalidScript{Details: "スクリプトに音声合成対象のセグメントがありません (無音指定のみ)"}
+	}
+
+	if len(preCalcErrors) == speechCount {
+		return nil, nil, newSynthesisBatchError(preCalcErrors)
 	}
 
 	return segments, preCalcErrors, nil
 }
 
+// prepareSpeechText は置換ルールと正規化を適用し、セグメントの読み上げ用テキストを決定します。
+func (e *Engine) prepareSpeechText(seg *engineSegment, cfg *ExecuteConfig) error {
+	seg.SpeechText = cfg.TextRules.Apply(seg.BaseSpeakerTag, seg.Text)
+	if cfg.Normalizer != nil {
+		seg.SpeechText = cfg.Normalizer.Normalize(seg.SpeechText)
+	}
+	if strings.TrimSpace(seg.SpeechText) == "" {
+		return fmt.Errorf("前処理後の読み上げテキストが空です (元のテキスト: %q)", seg.Text)
+	}
+	return nil
+}
+
 // runSynthesisBatch はセグメントの並列処理（レートリミットとセマフォ制御）を実行します。
-// 結果をインデックス順に格納するためのリストと、ランタイムエラーのリストを返します。
-func (e *Engine) runSynthesisBatch(ctx context.Context, segments []engineSegment) ([][]byte, []string) {
+// レートリミットはAPIを呼び出す直前 (processSegment 内) で適用され、キャッシュヒットしたセグメントは待機しません。
+// 結果をセグメントのインデックス順に格納したリストと、ランタイムエラーのリストを返します。
+func (e *Engine) runSynthesisBatch(ctx context.Context, segments []engineSegment, cfg *ExecuteConfig) ([]segmentResult, []*SegmentError) {
 	// 並列処理の準備
 	semaphore := make(chan struct{}, e.config.MaxParallelSegments)
 	wg := sync.WaitGroup{}
@@ -259,24 +840,24 @@ func (e *Engine) runSynthesisBatch(ctx context.Context, segments []engineSegment
 	// ループを中断するためのフラグ
 	shouldBreak := false
 
+	// 進捗の通知 (WithProgress 指定時のみ)
+	total := 0
+	for _, seg := range segments {
+		if !seg.IsSilence() && seg.Text != "" && seg.Err == nil {
+			total++
+		}
+	}
+	progress := newProgressReporter(cfg.Progress, total)
+
 	slog.Info("音声合成バッチ処理開始", "total_segments", len(segments), "max_parallel", e.config.MaxParallelSegments)
 
 	// セグメントごとの並列処理開始
 	for i, seg := range segments {
-		if seg.Text == "" || seg.Err != nil {
+		// 無音セグメントは結合時に生成するため、API呼び出しは行わない
+		if seg.IsSilence() || seg.Text == "" || seg.Err != nil {
 			continue
 		}
 
-		// レートリミット待機
-		if err := e.limiter.Wait(ctx); err != nil {
-			slog.InfoContext(ctx, "バッチ処理ループが外部コンテキストキャンセルにより終了しました。(レートリミット待機中)", "error", err)
-			shouldBreak = true
-		}
-
-		if shouldBreak {
-			break
-		}
-
 		// セマフォの確保。コンテキストキャンセルをチェック
 		select {
 		case <-ctx.Done():
@@ -296,10 +877,11 @@ func (e *Engine) runSynthesisBatch(ctx context.Context, segments []engineSegment
 			defer wg.Done()
 			defer func() { <-semaphore }()
 
-			segCtx, cancel := context.WithTimeout(ctx, e.config.SegmentTimeout)
-			defer cancel()
-
-			result := e.processSegment(segCtx, seg, i)
+			// タイムアウトは processSegment の中で試行ごとに適用される
+			progress.started(seg, i)
+			started := time.Now()
+			result := e.processSegment(ctx, seg, i, cfg, progress)
+			progress.finished(seg, result, time.Since(started))
 			resultsChan <- result
 
 		}(i, seg)
@@ -308,58 +890,29 @@ func (e *Engine) runSynthesisBatch(ctx context.Context, segments []engineSegment
 	// 並列処理終了後の集約準備
 	wg.Wait()
 	close(resultsChan)
+	progress.complete()
 
-	orderedAudioDataList := make([][]byte, len(segments))
-	var runtimeErrors []string
+	orderedResults := make([]segmentResult, len(segments))
+	var runtimeErrors []*SegmentError
+	cacheHits, cacheMisses := 0, 0
 
 	for res := range resultsChan {
+		orderedResults[res.index] = res
 		if res.err != nil {
-			runtimeErrors = append(runtimeErrors, res.err.Error())
-		} else if res.wavData != nil {
-			orderedAudioDataList[res.index] = res.wavData
-		}
-	}
-
-	return orderedAudioDataList, runtimeErrors
-}
-
-// finalizeOutput はバッチ結果を集約し、WAVデータを結合し、ファイルに書き出します。
-func (e *Engine) finalizeOutput(ctx context.Context, orderedAudioDataList [][]byte, outputWavFile string, preCalcErrors []string, runtimeErrors []string) error {
-	allErrors := append([]string{}, preCalcErrors...)
-	allErrors = append(allErrors, runtimeErrors...)
-
-	if len(allErrors) > 0 {
-		return &ErrSynthesisBatch{
-			TotalErrors: len(allErrors),
-			Details:     allErrors,
+			runtimeErrors = append(runtimeErrors, res.err)
+			// 失敗したセグメントはキャッシュの利用状況に含めない
+			continue
 		}
-	}
-
-	finalAudioDataList := make([][]byte, 0, len(orderedAudioDataList))
-	for _, data := range orderedAudioDataList {
-		if data != nil {
-			finalAudioDataList = append(finalAudioDataList, data)
+		if res.cacheHit {
+			cacheHits++
+		} else {
+			cacheMisses++
 		}
 	}
 
-	if len(finalAudioDataList) == 0 {
-		return fmt.Errorf("すべてのセグメントの合成に失敗したか、有効なセグメントがありませんでした")
-	}
-
-	combinedWavBytes, err := audio.CombineWavData(finalAudioDataList)
-	if err != nil {
-		return fmt.Errorf("WAVデータの結合に失敗しました: %w", err)
-	}
-
-	// 10. ファイルへの書き込み
-	slog.InfoContext(ctx, "全てのセグメントの合成と結合が完了しました。ファイル書き込みを行います。", "output_file", outputWavFile)
-
-	dir := filepath.Dir(outputWavFile)
-	if dir != "." {
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("出力ディレクトリの作成に失敗しました (%s): %w", dir, err)
-		}
+	if cfg.Cache != nil {
+		slog.InfoContext(ctx, "セグメントキャッシュの利用状況", "hits", cacheHits, "misses", cacheMisses)
 	}
 
-	return os.WriteFile(outputWavFile, combinedWavBytes, 0644)
+	return orderedResults, runtimeErrors
 }
@@ -0,0 +1,465 @@
+package voicevox
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
+)
+
+// ----------------------------------------------------------------------
+// テスト用の話者データ
+// ----------------------------------------------------------------------
+
+// fakeData はタグと Style ID の対応を保持する DataFinder (TagLister) です。
+// defaults は話者タグからデフォルトスタイルのタグへの対応です。
+type fakeData struct {
+	styles   map[string]int
+	defaults map[string]string
+}
+
+func (d *fakeData) GetStyleID(tag string) (int, bool) {
+	id, ok := d.styles[tag]
+	return id, ok
+}
+
+func (d *fakeData) GetDefaultTag(base string) (string, bool) {
+	tag, ok := d.defaults[base]
+	return tag, ok
+}
+
+func (d *fakeData) Tags() []string {
+	tags := make([]string, 0, len(d.styles))
+	for tag := range d.styles {
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// newTestData はずんだもんの2つのスタイルを登録した話者データを返します。
+func newTestData() *fakeData {
+	return &fakeData{
+		styles: map[string]int{
+			"[ずんだもん][ノーマル]": 3,
+			"[ずんだもん][ささやき]": 22,
+		},
+		defaults: map[string]string{"[ずんだもん]": "[ずんだもん][ノーマル]"},
+	}
+}
+
+// newTestEngine は API を呼び出さないテスト用の Engine を作成します。
+func newTestEngine(data DataFinder, opts ...EngineOption) *Engine {
+	return NewEngine(nil, data, parser.NewParser(), EngineConfig{
+		MaxParallelSegments: 1,
+		SegmentTimeout:      time.Second,
+		SegmentRateLimit:    time.Millisecond,
+	}, opts...)
+}
+
+// ----------------------------------------------------------------------
+// テスト用のAPIクライアント
+// ----------------------------------------------------------------------
+
+// fakeClient は /audio_query でテキストをクエリの kana に埋め込み、/synthesis でテキストに応じた長さの
+// 無音のWAV (24kHz・モノラル・16bit) を返す AudioQueryClient です。呼び出し回数をテキストごとに記録します。
+type fakeClient struct {
+	// durations はテキストごとの音声の長さです (未指定の場合は 100ms)。
+	durations map[string]time.Duration
+	// fail は /synthesis の呼び出しごとに呼ばれ、エラーを返すとその呼び出しを失敗させます。attempt は1始まりです。
+	fail func(text string, attempt int) error
+
+	mu        sync.Mutex
+	queries   map[string]int
+	syntheses map[string]int
+}
+
+func (c *fakeClient) RunAudioQuery(text string, styleID int, ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	if c.queries == nil {
+		c.queries = make(map[string]int)
+	}
+	c.queries[text]++
+	c.mu.Unlock()
+
+	kana, _ := json.Marshal(text)
+	return []byte(`{"accent_phrases":[],"speedScale":1,"pitchScale":0,"intonationScale":1,"volumeScale":1,"prePhonemeLength":0.1,"postPhonemeLength":0.1,"outputSamplingRate":24000,"outputStereo":false,"kana":` + string(kana) + `}`), nil
+}
+
+func (c *fakeClient) RunSynthesis(queryBody []byte, styleID int, ctx context.Context) ([]byte, error) {
+	var query struct {
+		Kana string `json:"kana"`
+	}
+	if err := json.Unmarshal(queryBody, &query); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.syntheses == nil {
+		c.syntheses = make(map[string]int)
+	}
+	c.syntheses[query.Kana]++
+	attempt := c.syntheses[query.Kana]
+	c.mu.Unlock()
+
+	if c.fail != nil {
+		if err := c.fail(query.Kana, attempt); err != nil {
+			return nil, err
+		}
+	}
+	d, ok := c.durations[query.Kana]
+	if !ok {
+		d = 100 * time.Millisecond
+	}
+	return testWAV(d), nil
+}
+
+// calls はテキストに対する /audio_query と /synthesis の呼び出し回数を返します。
+func (c *fakeClient) calls(text string) (queries, syntheses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.queries[text], c.syntheses[text]
+}
+
+// testWAV は 24kHz・モノラル・16bit の無音のWAVを生成します。
+func testWAV(d time.Duration) []byte {
+	const sampleRate, blockAlign = 24000, 2
+	dataSize := uint32(int(d.Seconds()*sampleRate) * blockAlign)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * blockAlign), uint16(blockAlign), uint16(16),
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// newFakeEngine は fakeClient と newTestData の話者データを使用するテスト用の Engine を作成します。
+func newFakeEngine(client *fakeClient, config EngineConfig) *Engine {
+	if config.SegmentTimeout == 0 {
+		config.SegmentTimeout = time.Second
+	}
+	config.SegmentRateLimit = time.Millisecond
+	return NewEngine(client, newTestData(), parser.NewParser(), config)
+}
+
+// ----------------------------------------------------------------------
+// Style ID の解決
+// ----------------------------------------------------------------------
+
+func TestGetStyleID(t *testing.T) {
+	// 各ステップは同じ Engine で順に実行し、前のステップのキャッシュが後のステップに影響しないことを確認する
+	steps := []struct {
+		name        string
+		tag         string
+		autoCorrect bool
+		want        int
+		wantErr     bool
+	}{
+		{name: "完全一致", tag: "[ずんだもん][ノーマル]", want: 3},
+		{name: "表記ゆれの吸収", tag: "［ずんだもん］［ノーマル］", want: 3},
+		{name: "話者タグのみはデフォルトスタイル", tag: "[ずんだもん]", want: 3},
+		{name: "未定義のスタイルはデフォルトにフォールバック", tag: "[ずんだもん][ささやく]", want: 3},
+		{name: "フォールバック後も自動修正が優先される", tag: "[ずんだもん][ささやく]", autoCorrect: true, want: 22},
+		{name: "自動修正の結果はキャッシュされない", tag: "[ずんだもん][ささやく]", want: 3},
+		{name: "未定義の話者", tag: "[めたん][ノーマル]", wantErr: true},
+	}
+
+	e := newTestEngine(newTestData())
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			cfg := newExecuteConfig()
+			cfg.AutoCorrectTags = step.autoCorrect
+
+			got, err := e.getStyleID(context.Background(), step.tag, baseTagOf(step.tag), 0, cfg)
+			if step.wantErr {
+				if err == nil {
+					t.Fatalf("getStyleID(%q) = %d, want error", step.tag, got)
+				}
+				return
+			}
+			if err != nil || got != step.want {
+				t.Fatalf("getStyleID(%q) = %d, %v; want %d", step.tag, got, err, step.want)
+			}
+		})
+	}
+}
+
+// baseTagOf はタグの最初の [..] 部分を返します。
+func baseTagOf(tag string) string {
+	if bases := baseTags([]string{tag}); len(bases) > 0 {
+		return bases[0]
+	}
+	return ""
+}
+
+// swappingData は最初の GetStyleID の呼び出し中に onLookup を実行する DataFinder です。
+type swappingData struct {
+	*fakeData
+	onLookup func()
+}
+
+func (d *swappingData) GetStyleID(tag string) (int, bool) {
+	if d.onLookup != nil {
+		hook := d.onLookup
+		d.onLookup = nil
+		hook()
+	}
+	return d.fakeData.GetStyleID(tag)
+}
+
+// TestGetStyleIDDuringSwap は検索中に話者データが差し替えられても、古いデータで解決した Style ID が
+// 新しいデータのキャッシュに残らないことを確認します。
+func TestGetStyleIDDuringSwap(t *testing.T) {
+	const tag = "[ずんだもん][ノーマル]"
+	newData := newTestData()
+	newData.styles[tag] = 99
+
+	old := &swappingData{fakeData: newTestData()}
+	e := newTestEngine(old)
+	old.onLookup = func() { e.swapData(newData, "") }
+
+	cfg := newExecuteConfig()
+	if got, err := e.getStyleID(context.Background(), tag, "[ずんだもん]", 0, cfg); err != nil || got != 3 {
+		t.Fatalf("差し替え中の getStyleID = %d, %v; want 3 (検索開始時のデータ)", got, err)
+	}
+	if got, err := e.getStyleID(context.Background(), tag, "[ずんだもん]", 0, cfg); err != nil || got != 99 {
+		t.Fatalf("差し替え後の getStyleID = %d, %v; want 99", got, err)
+	}
+}
+
+// ----------------------------------------------------------------------
+// セグメントの準備
+// ----------------------------------------------------------------------
+
+// TestPrepareSegmentsProsody はプロソディが エンジン全体 < 話者 < セグメント の順に優先されることを確認します。
+func TestPrepareSegmentsProsody(t *testing.T) {
+	data := newTestData()
+	data.styles["[めたん][ノーマル]"] = 2
+
+	cfg := newExecuteConfig()
+	WithProsody(api.Prosody{SpeedScale: api.Float(1.1), PitchScale: api.Float(0.1)})(cfg)
+	WithSpeakerProsody("[ずんだもん]", api.Prosody{SpeedScale: api.Float(1.2)})(cfg)
+
+	script := "[ずんだもん][ノーマル]{speed=1.3} セグメントの指定\n" +
+		"[ずんだもん][ノーマル] 話者の指定\n" +
+		"[めたん][ノーマル] エンジン全体の指定"
+	segments, _, err := newTestEngine(data).prepareSegments(context.Background(), script, cfg)
+	if err != nil {
+		t.Fatalf("prepareSegments: %v", err)
+	}
+
+	tests := []struct {
+		text      string
+		wantSpeed float64
+		wantPitch float64
+	}{
+		{text: "セグメントの指定", wantSpeed: 1.3, wantPitch: 0.1},
+		{text: "話者の指定", wantSpeed: 1.2, wantPitch: 0.1},
+		{text: "エンジン全体の指定", wantSpeed: 1.1, wantPitch: 0.1},
+	}
+	if len(segments) != len(tests) {
+		t.Fatalf("segments = %d, want %d", len(segments), len(tests))
+	}
+	for i, tt := range tests {
+		got := segments[i].ResolvedProsody
+		if segments[i].Text != tt.text || got.SpeedScale == nil || *got.SpeedScale != tt.wantSpeed ||
+			got.PitchScale == nil || *got.PitchScale != tt.wantPitch || got.VolumeScale != nil {
+			t.Errorf("segment %d (%s): ResolvedProsody = %+v, want speed=%v pitch=%v volume=nil", i, segments[i].Text, got, tt.wantSpeed, tt.wantPitch)
+		}
+	}
+}
+
+// ----------------------------------------------------------------------
+// 合成
+// ----------------------------------------------------------------------
+
+// TestSynthesize は結合済みWAVと、結合後の音声におけるセグメントの位置・Style ID が Result に反映され、
+// WithPartialOutput 指定時は失敗したセグメントをスキップした Result が ErrPartialSynthesis とともに返ることを確認します。
+func TestSynthesize(t *testing.T) {
+	const script = "[ずんだもん][ノーマル] 一つ目\n[ずんだもん][ささやき] 失敗する\n[ずんだもん][ささやき] 三つ目"
+	failing := func(text string, attempt int) error {
+		if text == "失敗する" {
+			return errors.New("synthesis failed")
+		}
+		return nil
+	}
+
+	type segment struct {
+		styleID    int
+		start, end time.Duration
+		skipped    bool
+	}
+	tests := []struct {
+		name         string
+		fail         func(text string, attempt int) error
+		opts         []ExecuteOption
+		wantErr      bool
+		wantSkipped  []int
+		wantSegments []segment
+		wantDuration time.Duration
+	}{
+		{
+			name: "すべてのセグメントを結合",
+			wantSegments: []segment{
+				{styleID: 3, start: 0, end: 100 * time.Millisecond},
+				{styleID: 22, start: 100 * time.Millisecond, end: 300 * time.Millisecond},
+				{styleID: 22, start: 300 * time.Millisecond, end: 350 * time.Millisecond},
+			},
+			wantDuration: 350 * time.Millisecond,
+		},
+		{
+			name:    "失敗したセグメントがあるとエラー",
+			fail:    failing,
+			wantErr: true,
+		},
+		{
+			name:        "部分出力では失敗したセグメントをスキップ",
+			fail:        failing,
+			opts:        []ExecuteOption{WithPartialOutput(false)},
+			wantErr:     true,
+			wantSkipped: []int{1},
+			wantSegments: []segment{
+				{styleID: 3, start: 0, end: 100 * time.Millisecond},
+				{styleID: 22, skipped: true},
+				{styleID: 22, start: 100 * time.Millisecond, end: 150 * time.Millisecond},
+			},
+			wantDuration: 150 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{
+				durations: map[string]time.Duration{"失敗する": 200 * time.Millisecond, "三つ目": 50 * time.Millisecond},
+				fail:      tt.fail,
+			}
+			result, err := newFakeEngine(client, EngineConfig{}).Synthesize(context.Background(), script, tt.opts...)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("Synthesize error = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantSegments == nil {
+				if result != nil {
+					t.Errorf("Synthesize の Result = %+v, want nil", result)
+				}
+				return
+			}
+
+			var partialErr *ErrPartialSynthesis
+			if tt.wantSkipped != nil && (!errors.As(err, &partialErr) || !slices.Equal(partialErr.SkippedIndices, tt.wantSkipped)) {
+				t.Errorf("Synthesize error = %v, want ErrPartialSynthesis (skipped %v)", err, tt.wantSkipped)
+			}
+			if len(result.Segments) != len(tt.wantSegments) {
+				t.Fatalf("Segments = %d, want %d", len(result.Segments), len(tt.wantSegments))
+			}
+			for i, want := range tt.wantSegments {
+				got := result.Segments[i]
+				if got.Index != i || got.StyleID != want.styleID || got.Start != want.start || got.End != want.end || got.Skipped != want.skipped {
+					t.Errorf("Segments[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+			if result.Duration != tt.wantDuration {
+				t.Errorf("Duration = %v, want %v", result.Duration, tt.wantDuration)
+			}
+			// 24kHz・モノラル・16bit のため、1秒あたり 48000 バイト
+			wantSize := 44 + int(tt.wantDuration.Seconds()*48000)
+			if len(result.WAV) != wantSize || !bytes.HasPrefix(result.WAV, []byte("RIFF")) {
+				t.Errorf("WAV = %d バイト, want %d バイトのWAV", len(result.WAV), wantSize)
+			}
+		})
+	}
+}
+
+// TestSynthesizeCues は字幕キューの時刻が結合後のサンプル数から求めた位置と一致し、無音区間にはキューを作成しないことを確認します。
+func TestSynthesizeCues(t *testing.T) {
+	// 100.125ms は 24kHz で 2403 サンプルちょうどで、ミリ秒単位に丸めた時刻とは一致しない
+	client := &fakeClient{durations: map[string]time.Duration{"一つ目": 100125 * time.Microsecond, "二つ目": 50 * time.Millisecond}}
+	script := "[ずんだもん][ノーマル] 一つ目[間:250ms]二つ目"
+	result, err := newFakeEngine(client, EngineConfig{}).Synthesize(context.Background(), script)
+	if err != nil {
+		t.Fatalf("Synthesize: %v", err)
+	}
+
+	want := []subtitle.Cue{
+		{Start: 0, End: 100125 * time.Microsecond, Speaker: "ずんだもん", Text: "一つ目"},
+		{Start: 350125 * time.Microsecond, End: 400125 * time.Microsecond, Speaker: "ずんだもん", Text: "二つ目"},
+	}
+	if !slices.Equal(result.Cues, want) {
+		t.Errorf("Cues = %+v, want %+v", result.Cues, want)
+	}
+	if result.Duration != 400125*time.Microsecond {
+		t.Errorf("Duration = %v, want 400.125ms", result.Duration)
+	}
+}
+
+// ----------------------------------------------------------------------
+// 話者データの更新
+// ----------------------------------------------------------------------
+
+func TestRefreshEngineVersion(t *testing.T) {
+	tests := []struct {
+		name          string
+		loadedVersion string
+		want          string
+	}{
+		{name: "取得したバージョンに更新する", loadedVersion: "0.15.0", want: "0.15.0"},
+		{name: "バージョンが不明な場合は維持する", loadedVersion: "", want: "0.14.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loader := func(ctx context.Context) (DataFinder, string, error) {
+				return newTestData(), tt.loadedVersion, nil
+			}
+			e := NewEngine(nil, newTestData(), parser.NewParser(), EngineConfig{EngineVersion: "0.14.0"}, WithDataLoader(loader))
+
+			if err := e.Refresh(context.Background()); err != nil {
+				t.Fatalf("Refresh: %v", err)
+			}
+			if got := e.engineVersion(); got != tt.want {
+				t.Errorf("engineVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCloseStopsSnapshotRefresh は Close でスナップショットからの再取得の再試行が停止することを確認します。
+func TestCloseStopsSnapshotRefresh(t *testing.T) {
+	attempts := make(chan struct{}, SnapshotRefreshMaxAttempts)
+	loader := func(ctx context.Context) (DataFinder, string, error) {
+		attempts <- struct{}{}
+		return nil, "", errors.New("engine is not ready")
+	}
+	e := newTestEngine(newTestData(), WithDataLoader(loader))
+
+	done := make(chan struct{})
+	go func() {
+		refreshFromSnapshot(e.bgCtx, e)
+		close(done)
+	}()
+
+	<-attempts
+	if err := e.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close の後も refreshFromSnapshot が停止しませんでした")
+	}
+}
@@ -2,21 +2,182 @@ package voicevox
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
+// ----------------------------------------------------------------------
+// セグメント単位のエラー (engine.go で利用)
+// ----------------------------------------------------------------------
+
+// SegmentPhase はセグメント処理のどの段階でエラーが発生したかを表します。
+type SegmentPhase string
+
+const (
+	PhaseStyleLookup    SegmentPhase = "style_lookup"    // 話者・スタイルタグからの Style ID 解決
+	PhaseTextPreprocess SegmentPhase = "text_preprocess" // 置換ルールなど読み上げ用テキストの前処理
+	PhaseRateLimit      SegmentPhase = "rate_limit"      // API呼び出し前のレートリミットの待機
+	PhaseAudioQuery     SegmentPhase = "audio_query"     // /audio_query の呼び出し
+	PhaseProsody        SegmentPhase = "prosody"         // クエリへのプロソディ適用
+	PhaseSynthesis      SegmentPhase = "synthesis"       // /synthesis の呼び出し
+	PhaseWavValidation  SegmentPhase = "wav_validation"  // 合成結果の WAV ヘッダー検証
+)
+
+// phaseLabels はエラーメッセージに使用する各段階の表示名です。
+var phaseLabels = map[SegmentPhase]string{
+	PhaseStyleLookup:    "Style IDの解決",
+	PhaseTextPreprocess: "テキスト前処理",
+	PhaseRateLimit:      "レートリミット待機",
+	PhaseAudioQuery:     "オーディオクエリ",
+	PhaseProsody:        "プロソディ適用",
+	PhaseSynthesis:      "音声合成",
+	PhaseWavValidation:  "WAVデータ検証",
+}
+
+// SegmentError は単一のセグメントの処理で発生したエラーです。
+// Err には api.ErrAPINetwork や audio.ErrInvalidWAVHeader などの元のエラーが格納され、
+// errors.Is / errors.As で判別できます。
+type SegmentError struct {
+	Index      int // セグメントのインデックス (0始まり)
+	SpeakerTag string
+	Text       string
+	Phase      SegmentPhase
+	Err        error
+	// Attempts は合成を試行した回数です (EngineConfig.Retry による再試行を含む)。事前計算のエラーでは 0 になります。
+	Attempts int
+}
+
+func (e *SegmentError) Error() string {
+	label, ok := phaseLabels[e.Phase]
+	if !ok {
+		label = string(e.Phase)
+	}
+	if e.Attempts > 1 {
+		return fmt.Sprintf("セグメント %d %s の%sに失敗しました (試行回数: %d回): %v", e.Index, e.SpeakerTag, label, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("セグメント %d %s の%sに失敗しました: %v", e.Index, e.SpeakerTag, label, e.Err)
+}
+
+// Unwrap は原因となったエラーを返します。
+func (e *SegmentError) Unwrap() error {
+	return e.Err
+}
+
 // ----------------------------------------------------------------------
 // バッチ処理エラー (engine.go で利用)
 // ----------------------------------------------------------------------
 
 // ErrSynthesisBatch は音声合成処理のバッチ全体で発生した複数のエラーをラップするカスタムエラー型です。
 // 事前計算エラーと実行時エラーの両方をまとめて呼び出し元に返します。
+// Errors はセグメントのインデックス順に並んだ構造化エラー、Details はその文字列表現です。
 type ErrSynthesisBatch struct {
 	TotalErrors int
 	Details     []string
+	Errors      []*SegmentError
+}
+
+// newSynthesisBatchError はセグメントエラーをインデックス順に整列し、ErrSynthesisBatch を構築します。
+func newSynthesisBatchError(errs []*SegmentError) *ErrSynthesisBatch {
+	sorted := append([]*SegmentError{}, errs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	details := make([]string, len(sorted))
+	for i, err := range sorted {
+		details[i] = err.Error()
+	}
+
+	return &ErrSynthesisBatch{
+		TotalErrors: len(sorted),
+		Details:     details,
+		Errors:      sorted,
+	}
 }
 
 func (e *ErrSynthesisBatch) Error() string {
 	return fmt.Sprintf("音声合成バッチ処理中に %d 件のエラーが発生しました:\n- %s",
 		e.TotalErrors, strings.Join(e.Details, "\n- "))
 }
+
+// Unwrap は各セグメントのエラーを返します (errors.Is/As 用)。
+func (e *ErrSynthesisBatch) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ErrPartialSynthesis は一部のセグメントをスキップして音声を出力したことを示します。
+// WithPartialOutput オプション指定時に返され、出力 (ファイルまたは Result) 自体は正常に作成されています。
+type ErrPartialSynthesis struct {
+	OutputFile        string // Execute の場合の出力ファイル (Synthesize の場合は空)
+	SkippedIndices    []int  // スキップしたセグメントのインデックス (0始まり)
+	FilledWithSilence bool   // スキップしたセグメントを無音で置き換えたかどうか
+	Batch             *ErrSynthesisBatch
+}
+
+func (e *ErrPartialSynthesis) Error() string {
+	indices := make([]string, len(e.SkippedIndices))
+	for i, index := range e.SkippedIndices {
+		indices[i] = fmt.Sprintf("%d", index)
+	}
+	output := "音声"
+	if e.OutputFile != "" {
+		output = e.OutputFile
+	}
+	msg := fmt.Sprintf("%d 件のセグメントをスキップして %s を出力しました (セグメント: %s)",
+		len(e.SkippedIndices), output, strings.Join(indices, ", "))
+	if e.Batch != nil {
+		msg += "\n" + e.Batch.Error()
+	}
+	return msg
+}
+
+// Unwrap は元となったバッチエラーを返します。
+func (e *ErrPartialSynthesis) Unwrap() error {
+	if e.Batch == nil {
+		return nil
+	}
+	return e.Batch
+}
+
+// ----------------------------------------------------------------------
+// タグ解決のエラー
+// ----------------------------------------------------------------------
+
+// ErrUnknownStyleTag は話者・スタイルタグ (およびデフォルトスタイル) に対応する Style ID が見つからないことを示します。
+// Suggestions には編集距離の近い登録済みのタグが格納されます。
+type ErrUnknownStyleTag struct {
+	Tag         string
+	Suggestions []string
+}
+
+func (e *ErrUnknownStyleTag) Error() string {
+	msg := fmt.Sprintf("話者・スタイルタグ %s (およびデフォルトスタイル) に対応するStyle IDが見つかりません", e.Tag)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(" (もしかして: %s ?)", strings.Join(e.Suggestions, ", "))
+	}
+	return msg
+}
+
+// ----------------------------------------------------------------------
+// スクリプトのエラー
+// ----------------------------------------------------------------------
+
+// ErrInvalidScript はスクリプトの解析に失敗したか、合成対象のセグメントが含まれていないことを示します。
+type ErrInvalidScript struct {
+	Details    string
+	WrappedErr error
+}
+
+func (e *ErrInvalidScript) Error() string {
+	if e.WrappedErr != nil {
+		return fmt.Sprintf("不正なスクリプト: %s (詳細: %v)", e.Details, e.WrappedErr)
+	}
+	return fmt.Sprintf("不正なスクリプト: %s", e.Details)
+}
+
+// Unwrap は原因となったエラーを返します (errors.Is/As 用)。
+func (e *ErrInvalidScript) Unwrap() error {
+	return e.WrappedErr
+}
@@ -2,12 +2,16 @@ package voicevox
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
 	"os"
 	"time"
 
 	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+	"github.com/shouni/go-voicevox/pkg/voicevox/dict"
 	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
 	"github.com/shouni/go-voicevox/pkg/voicevox/speaker"
 )
@@ -16,7 +20,7 @@ import (
 // No-op パターン
 // ----------------------------------------------------------------------
 
-// noopEngineExecutor は EngineExecutor インターフェースを満たすダミー実装です。
+// noopEngineExecutor は EngineExecutor と Synthesizer インターフェースを満たすダミー実装です。
 type noopEngineExecutor struct{}
 
 // Execute は何もしません。
@@ -25,16 +29,128 @@ func (n *noopEngineExecutor) Execute(ctx context.Context, script string, outputF
 	return nil
 }
 
+// Synthesize は何もせず、空の Result を返します。
+func (n *noopEngineExecutor) Synthesize(ctx context.Context, script string, opts ...ExecuteOption) (*Result, error) {
+	slog.Info("VOICEVOX機能は無効です。Synthesize呼び出しはスキップされました。", "script_length", len(script))
+	return &Result{}, nil
+}
+
+// SynthesizeTo は何も書き込まず、空の Result を返します。
+func (n *noopEngineExecutor) SynthesizeTo(ctx context.Context, w io.Writer, script string, opts ...ExecuteOption) (*Result, error) {
+	slog.Info("VOICEVOX機能は無効です。SynthesizeTo呼び出しはスキップされました。", "script_length", len(script))
+	return &Result{}, nil
+}
+
+// Close は何もしません。
+func (n *noopEngineExecutor) Close() error {
+	return nil
+}
+
+// ----------------------------------------------------------------------
+// Factory オプション (Functional Options Pattern)
+// ----------------------------------------------------------------------
+
+// factoryConfig は NewEngineExecutor の初期化時に適用されるオプション設定を保持する
+type factoryConfig struct {
+	dictFile        string
+	dictPrune       bool
+	loadOptions     []speaker.LoadOption
+	aliasFile       string
+	snapshotFile    string
+	refreshInterval time.Duration
+	readyTimeout    time.Duration
+	apiURL          string
+	engineConfig    EngineConfig
+}
+
+// FactoryOption は NewEngineExecutor にオプションを適用するための関数シグネチャ
+type FactoryOption func(*factoryConfig)
+
+// WithDictionaryFile は、起動時にエンジンのユーザー辞書と同期する辞書ファイル (YAML/JSON/CSV) を指定するオプション
+// prune が true の場合、辞書ファイルに定義されていない単語はエンジンから削除されます。
+func WithDictionaryFile(path string, prune bool) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.dictFile = path
+		cfg.dictPrune = prune
+	}
+}
+
+// WithAllSpeakers は、エンジンが提供するすべての話者・スタイルを、API上の名前から導出したタグで登録するオプション
+// 例: "[春日部つむぎ][ノーマル]"、"[ずんだもん][ヒソヒソ]"。SupportedSpeakers の短縮タグは別名として引き続き使用できます。
+func WithAllSpeakers() FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.loadOptions = append(cfg.loadOptions, speaker.WithAllSpeakers())
+	}
+}
+
+// WithSpeakerAliases は、スクリプトで使用する任意のタグ (例: "[ナレーター]") を話者に対応付けるオプション
+func WithSpeakerAliases(aliases ...speaker.Alias) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.loadOptions = append(cfg.loadOptions, speaker.WithAliases(aliases...))
+	}
+}
+
+// WithSpeakerAliasFile は、話者の別名を YAML/JSON ファイルから読み込むオプション
+func WithSpeakerAliasFile(path string) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.aliasFile = path
+	}
+}
+
+// WithSpeakerSnapshot は、話者データのスナップショットファイルを指定するオプション
+// ファイルが存在する場合は /speakers を待たずにスナップショットから開始し、エンジンからの再取得をバックグラウンドで行います。
+// エンジンから取得した話者データは、このファイルに保存されます。
+func WithSpeakerSnapshot(path string) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.snapshotFile = path
+	}
+}
+
+// WithRefreshInterval は、話者データをエンジンから定期的に再取得する間隔を指定するオプション
+// 音声ライブラリの追加やエンジンの再起動による Style ID の変更が、サービスを再起動せずに反映されます。
+// 定期更新は NewEngineExecutor に渡した ctx がキャンセルされると停止します。
+func WithRefreshInterval(interval time.Duration) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.refreshInterval = interval
+	}
+}
+
+// WithReadyTimeout は、初期化の最初にエンジンが応答可能になるまで待機する期限を指定するオプション
+// コンテナの起動直後などでエンジンがまだ応答しない場合も、期限内であれば /version への疎通確認を繰り返して待ちます。
+func WithReadyTimeout(timeout time.Duration) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.readyTimeout = timeout
+	}
+}
+
+// WithAPIURL は、VOICEVOXエンジンのURLを指定するオプション
+// 指定した場合は環境変数 VOICEVOX_API_URL より優先されます。
+func WithAPIURL(apiURL string) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.apiURL = apiURL
+	}
+}
+
+// WithEngineConfig は、並列数・タイムアウト・レートリミット・再試行などの EngineConfig を指定するオプション
+// ゼロ値のフィールドには既定値 (DefaultMaxParallelSegments など) が使用されます。EngineVersion はエンジンから取得した値で上書きされます。
+func WithEngineConfig(config EngineConfig) FactoryOption {
+	return func(cfg *factoryConfig) {
+		cfg.engineConfig = config
+	}
+}
+
 // ----------------------------------------------------------------------
 // Factory 関数
 // ----------------------------------------------------------------------
 
 // NewEngineExecutor は、VOICEVOXエンジンへの接続、話者データのロードを行い、
 // EngineExecutorインターフェースを実装した具象型を組み立てて返します。
+// 返された Executor は io.Closer を実装しており、Close でバックグラウンドでの話者データの再取得を停止できます。
 func NewEngineExecutor(
 	ctx context.Context,
 	httpTimeout time.Duration,
 	voicevoxOutput bool,
+	opts ...FactoryOption,
 ) (EngineExecutor, error) {
 	// VOICEVOX機能を使用しない場合はダミーのExecutorを返す (No-opパターン)
 	if !voicevoxOutput {
@@ -42,8 +158,16 @@ func NewEngineExecutor(
 		return &noopEngineExecutor{}, nil
 	}
 
+	cfg := &factoryConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	// 1-1. API URLの設定
-	voicevoxAPIURL := os.Getenv("VOICEVOX_API_URL")
+	voicevoxAPIURL := cfg.apiURL
+	if voicevoxAPIURL == "" {
+		voicevoxAPIURL = os.Getenv("VOICEVOX_API_URL")
+	}
 	if voicevoxAPIURL == "" {
 		voicevoxAPIURL = defaultVoicevoxAPIURL
 		slog.Warn("VOICEVOX_API_URL 環境変数が設定されていません。", "default_url", voicevoxAPIURL)
@@ -52,30 +176,225 @@ func NewEngineExecutor(
 	// 1-2. クライアントの初期化 (api.NewClient は api.Client を返す)
 	voicevoxClient := api.NewClient(voicevoxAPIURL, httpTimeout)
 
+	// 1-3. エンジンの起動待ち (WithReadyTimeout が指定されている場合のみ)
+	var engineVersion string
+	if cfg.readyTimeout > 0 {
+		slog.Info("VOICEVOXエンジンの起動を待機しています...", "api_url", voicevoxAPIURL, "timeout", cfg.readyTimeout.String())
+		readyCtx, cancel := context.WithTimeout(ctx, cfg.readyTimeout)
+		version, err := voicevoxClient.WaitReady(readyCtx, api.Backoff{})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("VOICEVOXエンジンが %s 以内に応答可能になりませんでした: %w", cfg.readyTimeout, err)
+		}
+		engineVersion = version
+	}
+
+	// 1-4. 宣言的な辞書ファイルとの差分同期 (WithDictionaryFile が指定されている場合のみ)
+	if cfg.dictFile != "" {
+		report, err := dict.SyncFile(ctx, voicevoxClient, cfg.dictFile, dict.WithPrune(cfg.dictPrune))
+		if err != nil {
+			return nil, fmt.Errorf("辞書ファイル %s の同期に失敗しました: %w", cfg.dictFile, err)
+		}
+		slog.Info("辞書ファイルとユーザー辞書の差分を反映しました。",
+			"dict_file", cfg.dictFile,
+			"summary", report.String(),
+			"added", report.Added,
+			"updated", report.Updated,
+			"deleted", report.Deleted)
+	}
+
+	// 1-5. 話者の別名設定の読み込み
+	if cfg.aliasFile != "" {
+		aliases, err := speaker.LoadAliasFile(cfg.aliasFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.loadOptions = append(cfg.loadOptions, speaker.WithAliases(aliases...))
+		slog.Info("話者の別名設定を読み込みました。", "alias_file", cfg.aliasFile, "aliases_count", len(aliases))
+	}
+
 	slog.Info("VOICEVOX話者スタイルデータをロード中...")
 
 	// 2. SpeakerDataのロード (Engine初期化の必須依存)
-	speakerData, loadErr := speaker.LoadSpeakers(ctx, voicevoxClient)
-	if loadErr != nil {
-		return nil, fmt.Errorf("VOICEVOXエンジンへの接続または話者データのロードに失敗しました: %w", loadErr)
+	// スナップショットが指定されている場合はそこから開始し、エンジンからの再取得はバックグラウンドで行う
+	var speakerData *speaker.SpeakerData
+	snapshot, fromSnapshot := loadSpeakerSnapshot(cfg.snapshotFile)
+	if fromSnapshot {
+		speakerData = snapshot.SpeakerData()
+		// 起動待ちをしていない場合、エンジンのバージョンはスナップショット作成時のものを引き継ぐ
+		if engineVersion == "" {
+			engineVersion = snapshot.EngineVersion
+		}
+	} else {
+		// 起動待ちでバージョンを取得済みの場合は /version を再度呼び出さない
+		loaded, version, loadErr := loadEngineData(ctx, voicevoxClient, cfg, engineVersion)
+		if loadErr != nil {
+			return nil, fmt.Errorf("VOICEVOXエンジンへの接続または話者データのロードに失敗しました: %w", loadErr)
+		}
+		speakerData, engineVersion = loaded, version
+	}
+	slog.Info("VOICEVOX話者スタイルデータのロード完了。",
+		"styles_count", len(speakerData.StyleIDMap),
+		"from_snapshot", fromSnapshot,
+		"engine_version", engineVersion)
+
+	// 2-2. ユーザー辞書のフィンガープリントの取得 (セグメントキャッシュのキーに使用)
+	// スナップショットから起動した場合はエンジンが応答しない可能性があるため、疎通を確認済みの場合のみ取得する
+	var userDictFingerprint string
+	if !fromSnapshot || cfg.readyTimeout > 0 || cfg.dictFile != "" {
+		userDictFingerprint = fetchUserDictFingerprint(ctx, voicevoxClient)
 	}
-	slog.Info("VOICEVOX話者スタイルデータのロード完了。", "styles_count", len(speakerData.StyleIDMap))
 
 	// 3. EngineConfigの設定
 	engineConfig := EngineConfig{
 		MaxParallelSegments: DefaultMaxParallelSegments,
 		SegmentTimeout:      DefaultSegmentTimeout,
 		SegmentRateLimit:    DefaultSegmentRateLimit,
+		EngineVersion:       engineVersion,
+		UserDictFingerprint: userDictFingerprint,
+		RefreshInterval:     cfg.refreshInterval,
+	}
+	if cfg.engineConfig.MaxParallelSegments > 0 {
+		engineConfig.MaxParallelSegments = cfg.engineConfig.MaxParallelSegments
+	}
+	if cfg.engineConfig.SegmentTimeout > 0 {
+		engineConfig.SegmentTimeout = cfg.engineConfig.SegmentTimeout
+	}
+	if cfg.engineConfig.SegmentRateLimit > 0 {
+		engineConfig.SegmentRateLimit = cfg.engineConfig.SegmentRateLimit
+	}
+	if cfg.engineConfig.RefreshInterval > 0 {
+		engineConfig.RefreshInterval = cfg.engineConfig.RefreshInterval
+	}
+	if cfg.engineConfig.Retry.MaxAttempts > 0 {
+		engineConfig.Retry = cfg.engineConfig.Retry
 	}
 
 	// 4. Engineの組み立てとExecutorとしての返却
 	textParser := parser.NewParser()
 
+	// 話者データの再取得 (Refresh) では、起動時と同じ設定でロードし、エンジンのバージョンとスナップショットも更新する
+	loader := func(ctx context.Context) (DataFinder, string, error) {
+		return loadEngineData(ctx, voicevoxClient, cfg, "")
+	}
+
 	// NewEngine を呼び出す (engine.go で定義)
-	voicevoxExecutor := NewEngine(voicevoxClient, speakerData, textParser, engineConfig)
+	voicevoxExecutor := NewEngine(voicevoxClient, speakerData, textParser, engineConfig, WithDataLoader(loader))
 	slog.Info("VOICEVOX Executorの初期化が完了しました。",
+		"engine_version", engineConfig.EngineVersion,
 		"max_parallel", engineConfig.MaxParallelSegments,
-		"segment_timeout", engineConfig.SegmentTimeout.String())
+		"segment_timeout", engineConfig.SegmentTimeout.String(),
+		"max_attempts", max(engineConfig.Retry.MaxAttempts, 1))
+
+	// スナップショットからの再取得は呼び出し元の ctx より長く続くため、Engine の Close で停止する
+	if fromSnapshot {
+		go refreshFromSnapshot(voicevoxExecutor.bgCtx, voicevoxExecutor)
+	}
+	voicevoxExecutor.StartAutoRefresh(ctx)
 
 	return voicevoxExecutor, nil
 }
+
+// ----------------------------------------------------------------------
+// 話者データのスナップショット
+// ----------------------------------------------------------------------
+
+// loadSpeakerSnapshot はスナップショットファイルから話者データを読み込みます。
+// ファイルが指定されていない、存在しない、または読み込めない場合は false を返します。
+func loadSpeakerSnapshot(path string) (*speaker.Snapshot, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	snapshot, err := speaker.LoadSnapshot(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("話者データのスナップショットを読み込めません。エンジンから取得します。", "snapshot_file", path, "error", err)
+		}
+		return nil, false
+	}
+
+	slog.Info("話者データのスナップショットから開始します。",
+		"snapshot_file", path,
+		"created_at", snapshot.CreatedAt,
+		"engine_version", snapshot.EngineVersion)
+	return snapshot, true
+}
+
+// saveSpeakerSnapshot はエンジンから取得した話者データをスナップショットとして保存します。
+// 保存に失敗しても処理は継続します。
+func saveSpeakerSnapshot(path string, data *speaker.SpeakerData, engineVersion string) {
+	if path == "" {
+		return
+	}
+	if err := speaker.SaveSnapshot(path, data.Snapshot(engineVersion)); err != nil {
+		slog.Warn("話者データのスナップショットの保存に失敗しました。", "snapshot_file", path, "error", err)
+		return
+	}
+	slog.Info("話者データのスナップショットを保存しました。", "snapshot_file", path)
+}
+
+// refreshFromSnapshot はバックグラウンドでエンジンから話者データを取得し、スナップショットのデータと差し替えます。
+// エンジンの起動を待つため、成功するまで一定間隔で再試行します。ctx がキャンセルされる (Engine の Close) と停止します。
+func refreshFromSnapshot(ctx context.Context, engine *Engine) {
+	for attempt := 1; attempt <= SnapshotRefreshMaxAttempts; attempt++ {
+		err := engine.Refresh(ctx)
+		if err == nil {
+			slog.InfoContext(ctx, "エンジンから話者データを再取得し、スナップショットのデータと差し替えました。", "attempt", attempt)
+			return
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		slog.WarnContext(ctx, "エンジンからの話者データの再取得に失敗しました。スナップショットのデータで継続します。",
+			"attempt", attempt, "max_attempts", SnapshotRefreshMaxAttempts, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(SnapshotRefreshRetryInterval):
+		}
+	}
+}
+
+// ----------------------------------------------------------------------
+// エンジン情報
+// ----------------------------------------------------------------------
+
+// loadEngineData はエンジンから話者データとバージョンを取得し、スナップショットを保存します。
+// 起動時と Refresh の共通処理です。knownVersion が空でない場合は /version を呼び出さずにその値を使用します。
+func loadEngineData(ctx context.Context, client *api.Client, cfg *factoryConfig, knownVersion string) (*speaker.SpeakerData, string, error) {
+	data, err := speaker.LoadSpeakers(ctx, client, cfg.loadOptions...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	version := knownVersion
+	if version == "" {
+		version = fetchEngineVersion(ctx, client)
+	}
+	saveSpeakerSnapshot(cfg.snapshotFile, data, version)
+	return data, version, nil
+}
+
+// fetchEngineVersion はエンジンのバージョンを取得します。
+// バージョンはログとキャッシュキーにのみ使用するため、取得に失敗しても空文字列を返して処理を継続します。
+func fetchEngineVersion(ctx context.Context, client *api.Client) string {
+	version, err := client.GetVersion(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "VOICEVOXエンジンのバージョンを取得できませんでした。", "error", err)
+		return ""
+	}
+	return version
+}
+
+// fetchUserDictFingerprint はユーザー辞書のフィンガープリントを取得します。
+// fetchEngineVersion と同様に、取得に失敗しても空文字列を返して処理を継続します。
+func fetchUserDictFingerprint(ctx context.Context, client *api.Client) string {
+	fingerprint, err := dict.FetchFingerprint(ctx, client)
+	if err != nil {
+		slog.WarnContext(ctx, "ユーザー辞書のフィンガープリントを取得できませんでした。", "error", err)
+		return ""
+	}
+	return fingerprint
+}
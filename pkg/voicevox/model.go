@@ -2,6 +2,11 @@ package voicevox
 
 import (
 	"context"
+	"io"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
 )
 
 // ----------------------------------------------------------------------
@@ -16,14 +21,81 @@ type EngineExecutor interface {
 	Execute(ctx context.Context, scriptContent string, outputWavFile string, opts ...ExecuteOption) error
 }
 
+// Synthesizer はファイルを経由せずに合成結果を返す Executor が実装するインターフェースです。
+// NewEngineExecutor が返す Executor は、型アサーションでこのインターフェースを取得できます。
+type Synthesizer interface {
+	// Synthesize はスクリプトを実行し、結合したWAVデータとセグメントのメタデータを返します。
+	Synthesize(ctx context.Context, scriptContent string, opts ...ExecuteOption) (*Result, error)
+	// SynthesizeTo はスクリプトを実行し、結合したWAVデータを w に書き込みます。
+	SynthesizeTo(ctx context.Context, w io.Writer, scriptContent string, opts ...ExecuteOption) (*Result, error)
+}
+
 // DataFinder は、Engine が Style ID を検索するために SpeakerData に要求するメソッドを定義します。
 type DataFinder interface {
 	GetStyleID(combinedTag string) (int, bool)
 	GetDefaultTag(speakerToolTag string) (string, bool)
 }
 
+// Refresher は話者データをエンジンから再取得できる Executor が実装するインターフェースです。
+// NewEngineExecutor が返す Executor は、型アサーションでこのインターフェースを取得できます。
+type Refresher interface {
+	Refresh(ctx context.Context) error
+}
+
+// TagLister は登録されているすべての話者・スタイルタグを列挙します。
+// DataFinder がこれを実装している場合、Engine はタグの表記ゆれの吸収と候補の提示に利用します。
+type TagLister interface {
+	Tags() []string
+}
+
+// StyleLister は登録されている話者・スタイルタグと Style ID の対応を返します。
+// NewEngineExecutor が返す Executor は、型アサーションでこのインターフェースを取得できます。
+type StyleLister interface {
+	Styles() map[string]int
+}
+
+// Planner はAPIを呼び出さずに、スクリプトの解析結果と Style ID の解決結果を返します。
+// NewEngineExecutor が返す Executor は、型アサーションでこのインターフェースを取得できます。
+type Planner interface {
+	Plan(ctx context.Context, scriptContent string, opts ...ExecuteOption) ([]SegmentInfo, error)
+}
+
 // AudioQueryClient は Client が満たすべき API 呼び出しインターフェース
 type AudioQueryClient interface {
 	RunAudioQuery(text string, styleID int, ctx context.Context) ([]byte, error)
 	RunSynthesis(queryBody []byte, styleID int, ctx context.Context) ([]byte, error)
 }
+
+// ----------------------------------------------------------------------
+// 合成結果
+// ----------------------------------------------------------------------
+
+// Result は Synthesize の結果です。結合済みのWAVデータと、セグメントごとのメタデータを保持します。
+type Result struct {
+	WAV      []byte
+	Duration time.Duration
+	Segments []SegmentInfo
+	// Cues は音声を出力したセグメントごとの字幕キューです。
+	Cues []subtitle.Cue
+}
+
+// SegmentInfo は各セグメントの処理結果と、結合後の音声における位置を表します。
+type SegmentInfo struct {
+	Index      int
+	Kind       parser.SegmentKind
+	SpeakerTag string
+	Text       string
+	// SpeechText は置換ルールなどの前処理後に /audio_query へ渡したテキストです。
+	SpeechText string
+	StyleID    int
+	Start      time.Duration
+	End        time.Duration
+	CacheHit   bool
+	// Skipped は合成に失敗し、出力から除外 (または無音で置換) されたことを示します。
+	Skipped bool
+}
+
+// WriteSubtitles は指定された形式で字幕を w に書き出します。
+func (r *Result) WriteSubtitles(w io.Writer, format subtitle.Format) error {
+	return subtitle.Write(w, format, r.Cues)
+}
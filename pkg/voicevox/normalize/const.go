@@ -0,0 +1,40 @@
+package normalize
+
+// ----------------------------------------------------------------------
+// 読みの定義
+// ----------------------------------------------------------------------
+
+// digitReadings は数字1文字の漢数字表記です。
+var digitReadings = [10]string{"〇", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+// smallUnits は4桁以内の位取り (一の位、十、百、千) です。
+var smallUnits = [4]string{"", "十", "百", "千"}
+
+// largeUnits は4桁ごとの位取りです。
+var largeUnits = [5]string{"", "万", "億", "兆", "京"}
+
+// DecimalPoint は小数点の読みです。
+const DecimalPoint = "点"
+
+// letterReadings は英字1文字のカタカナ読みです。
+var letterReadings = map[rune]string{
+	'A': "エー", 'B': "ビー", 'C': "シー", 'D': "ディー", 'E': "イー",
+	'F': "エフ", 'G': "ジー", 'H': "エイチ", 'I': "アイ", 'J': "ジェー",
+	'K': "ケー", 'L': "エル", 'M': "エム", 'N': "エヌ", 'O': "オー",
+	'P': "ピー", 'Q': "キュー", 'R': "アール", 'S': "エス", 'T': "ティー",
+	'U': "ユー", 'V': "ブイ", 'W': "ダブリュー", 'X': "エックス", 'Y': "ワイ",
+	'Z': "ゼット",
+}
+
+// unitReadings は数値の直後に置かれる単位の読みです。
+// 正規表現の選択肢として長いものから照合されます。
+var unitReadings = map[string]string{
+	"TB": "テラバイト", "GB": "ギガバイト", "MB": "メガバイト", "KB": "キロバイト", "kB": "キロバイト",
+	"Gbps": "ギガビーピーエス", "Mbps": "メガビーピーエス", "bps": "ビーピーエス",
+	"GHz": "ギガヘルツ", "MHz": "メガヘルツ", "kHz": "キロヘルツ", "Hz": "ヘルツ",
+	"km": "キロメートル", "cm": "センチメートル", "mm": "ミリメートル", "m": "メートル",
+	"kg": "キログラム", "mg": "ミリグラム", "g": "グラム",
+	"ms": "ミリ秒", "px": "ピクセル", "fps": "エフピーエス",
+	"mL": "ミリリットル", "ml": "ミリリットル", "L": "リットル",
+	"%": "パーセント", "℃": "度", "°C": "度",
+}
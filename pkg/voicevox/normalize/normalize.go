@@ -0,0 +1,46 @@
+package normalize
+
+// ----------------------------------------------------------------------
+// ノーマライザーの定義
+// ----------------------------------------------------------------------
+
+// Normalizer は読み上げ用テキストを、VOICEVOXエンジンが正しく読める表記に変換します。
+type Normalizer interface {
+	Normalize(text string) string
+}
+
+// Func は関数を Normalizer として扱うためのアダプターです。
+type Func func(text string) string
+
+// Normalize は f(text) を返します。
+func (f Func) Normalize(text string) string {
+	return f(text)
+}
+
+// Chain は複数の Normalizer を順に適用します。
+type Chain []Normalizer
+
+// Normalize はすべての Normalizer を定義順に適用します。
+func (c Chain) Normalize(text string) string {
+	for _, n := range c {
+		text = n.Normalize(text)
+	}
+	return text
+}
+
+// Default は標準のノーマライザーを推奨される順序で返します。
+// 記号の除去 → バージョン → 日付・時刻 → 単位 → 英字略語 → 数字 の順に変換されるため、
+// 例えば "3.5GB" は "三点五ギガバイト" に、"2025/10/16" は "二千二十五年十月十六日" になります。
+// バージョンを日付より先に変換するのは、"v2025.10.1" のようなバージョン表記が日付として読まれないようにするためです。
+func Default() Chain {
+	return Chain{
+		Markup(),
+		Emoji(),
+		Versions(),
+		Dates(),
+		Times(),
+		Units(),
+		Acronyms(nil),
+		Numbers(),
+	}
+}
@@ -0,0 +1,54 @@
+package normalize
+
+import "testing"
+
+func TestMarkup(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "太字", in: "**重要**なお知らせ", want: "重要なお知らせ"},
+		{name: "斜体", in: "これは*強調*です", want: "これは強調です"},
+		{name: "打ち消し線とインラインコード", in: "~~旧版~~と`go test`", want: "旧版とgo test"},
+		{name: "単独のアスタリスクは残す", in: "3*4は12", want: "3*4は12"},
+		{name: "英数字に挟まれたアスタリスクは残す", in: "2*3 と 4*5", want: "2*3 と 4*5"},
+		{name: "見出しとリスト", in: "## 見出し\n- 項目", want: "見出し\n項目"},
+		{name: "リンクは表示テキストを残す", in: "[公式サイト](https://example.com)を参照", want: "公式サイトを参照"},
+		{name: "HTMLタグ", in: "<b>太字</b>です", want: "太字です"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markup().Normalize(tt.in); got != tt.want {
+				t.Errorf("Markup(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefault(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "日付", in: "2025/10/16に公開", want: "二千二十五年十月十六日に公開"},
+		{name: "時刻", in: "10:30に開始", want: "十時三十分に開始"},
+		{name: "バージョン", in: "v1.24をリリース", want: "バージョン一点二十四をリリース"},
+		{name: "日付形式のバージョンは日付として読まない", in: "v2025.10.1をリリース", want: "バージョン二千二十五点十点一をリリース"},
+		{name: "単位", in: "3.5GBの空き", want: "三点五ギガバイトの空き"},
+		{name: "英字略語", in: "APIを使う", want: "エーピーアイを使う"},
+		{name: "桁区切りの数字", in: "1,234円", want: "千二百三十四円"},
+		{name: "記号と絵文字の除去", in: "**注意**😀です", want: "注意です"},
+		{name: "数式のアスタリスクは残す", in: "3*4は12", want: "三*四は十二"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Default().Normalize(tt.in); got != tt.want {
+				t.Errorf("Default().Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package normalize
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ----------------------------------------------------------------------
+// 数字・日付・時刻・単位
+// ----------------------------------------------------------------------
+
+var (
+	reNumber  = regexp.MustCompile(`\d{1,3}(?:,\d{3})+(?:\.\d+)?|\d+(?:\.\d+)?`)
+	reDate    = regexp.MustCompile(`(\d{4})[/\-.](\d{1,2})[/\-.](\d{1,2})`)
+	reTime    = regexp.MustCompile(`(\d{1,2}):(\d{2})(?::(\d{2}))?`)
+	reVersion = regexp.MustCompile(`\b[vV](\d+(?:\.\d+)+|\d+)\b`)
+	reUnit    = regexp.MustCompile(buildUnitPattern())
+)
+
+// Numbers は数字を漢数字の表記に変換します。
+// 桁区切りのカンマを含む整数は位取り付きで (1,234 → 千二百三十四)、小数部は1桁ずつ (3.14 → 三点一四) 変換され、
+// 先頭が0の数字列 (電話番号や番号など) は1桁ずつ読み上げます。
+func Numbers() Normalizer {
+	return Func(func(text string) string {
+		return reNumber.ReplaceAllStringFunc(text, NumberToKanji)
+	})
+}
+
+// Dates は "2025/10/16" や "2025-10-16" 形式の日付を "2025年10月16日" に変換します。
+func Dates() Normalizer {
+	return Func(func(text string) string {
+		return reDate.ReplaceAllString(text, "${1}年${2}月${3}日")
+	})
+}
+
+// Times は "10:30" や "10:30:15" 形式の時刻を "10時30分15秒" に変換します。
+func Times() Normalizer {
+	return Func(func(text string) string {
+		return reTime.ReplaceAllStringFunc(text, func(match string) string {
+			parts := reTime.FindStringSubmatch(match)
+			result := trimZero(parts[1]) + "時"
+			if parts[2] != "00" {
+				result += trimZero(parts[2]) + "分"
+			}
+			if parts[3] != "" && parts[3] != "00" {
+				result += trimZero(parts[3]) + "秒"
+			}
+			return result
+		})
+	})
+}
+
+// Versions は "v1.24" 形式のバージョン表記を "バージョン一点二十四" のように変換します。
+// 各区切りは小数ではなく整数として読み上げます。
+func Versions() Normalizer {
+	return Func(func(text string) string {
+		return reVersion.ReplaceAllStringFunc(text, func(match string) string {
+			parts := strings.Split(match[1:], ".")
+			for i, part := range parts {
+				parts[i] = NumberToKanji(part)
+			}
+			return "バージョン" + strings.Join(parts, DecimalPoint)
+		})
+	})
+}
+
+// Units は数値の直後の単位 (GB, km, % など) をカタカナ・漢字の読みに変換します。
+func Units() Normalizer {
+	return Func(func(text string) string {
+		return reUnit.ReplaceAllStringFunc(text, func(match string) string {
+			parts := reUnit.FindStringSubmatch(match)
+			unit := parts[2]
+			if unit == "" {
+				unit = parts[3]
+			}
+			return parts[1] + unitReadings[unit]
+		})
+	})
+}
+
+// buildUnitPattern は unitReadings から単位を照合する正規表現を組み立てます。
+// 英字の単位は後ろに英字が続かない場合のみ照合されます (例: "5m" は照合し、"5min" は照合しない)。
+func buildUnitPattern() string {
+	var letters, symbols []string
+	for unit := range unitReadings {
+		if unit[0] >= 'A' && unit[0] <= 'z' {
+			letters = append(letters, regexp.QuoteMeta(unit))
+		} else {
+			symbols = append(symbols, regexp.QuoteMeta(unit))
+		}
+	}
+	byLength := func(list []string) {
+		sort.Slice(list, func(i, j int) bool {
+			if len(list[i]) != len(list[j]) {
+				return len(list[i]) > len(list[j])
+			}
+			return list[i] < list[j]
+		})
+	}
+	byLength(letters)
+	byLength(symbols)
+	return `(\d+(?:\.\d+)?)\s?(?:(` + strings.Join(letters, "|") + `)\b|(` + strings.Join(symbols, "|") + `))`
+}
+
+// NumberToKanji は数字列 (カンマ区切り・小数を含む) を漢数字の表記に変換します。
+// 変換できない場合は元の文字列を返します。
+func NumberToKanji(s string) string {
+	integer, fraction, hasFraction := strings.Cut(strings.ReplaceAll(s, ",", ""), ".")
+
+	var b strings.Builder
+	if len(integer) > 1 && integer[0] == '0' || len(integer) > 20 {
+		b.WriteString(digitsToKanji(integer))
+	} else {
+		n, err := strconv.ParseUint(integer, 10, 64)
+		if err != nil {
+			return s
+		}
+		b.WriteString(IntToKanji(n))
+	}
+
+	if hasFraction {
+		b.WriteString(DecimalPoint)
+		b.WriteString(digitsToKanji(fraction))
+	}
+	return b.String()
+}
+
+// IntToKanji は整数を位取り付きの漢数字 (例: 12345 → 一万二千三百四十五) に変換します。
+func IntToKanji(n uint64) string {
+	if n == 0 {
+		return digitReadings[0]
+	}
+
+	var groups []string
+	for unit := 0; n > 0 && unit < len(largeUnits); unit++ {
+		group := n % 10000
+		n /= 10000
+		if group > 0 {
+			groups = append(groups, groupToKanji(int(group))+largeUnits[unit])
+		}
+	}
+
+	// 上位の桁から並べる
+	var b strings.Builder
+	for i := len(groups) - 1; i >= 0; i-- {
+		b.WriteString(groups[i])
+	}
+	return b.String()
+}
+
+// groupToKanji は4桁以内の整数を漢数字に変換します。十・百・千の前の「一」は省略します。
+func groupToKanji(n int) string {
+	var b strings.Builder
+	for place := len(smallUnits) - 1; place >= 0; place-- {
+		digit := n
+		for i := 0; i < place; i++ {
+			digit /= 10
+		}
+		digit %= 10
+		if digit == 0 {
+			continue
+		}
+		if digit != 1 || place == 0 {
+			b.WriteString(digitReadings[digit])
+		}
+		b.WriteString(smallUnits[place])
+	}
+	return b.String()
+}
+
+// digitsToKanji は数字列を1桁ずつ漢数字に変換します。
+func digitsToKanji(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteString(digitReadings[r-'0'])
+		}
+	}
+	return b.String()
+}
+
+// trimZero は時刻の "09" のような先頭の0を取り除きます。
+func trimZero(s string) string {
+	if trimmed := strings.TrimLeft(s, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
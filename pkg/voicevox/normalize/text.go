@@ -0,0 +1,102 @@
+package normalize
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// ----------------------------------------------------------------------
+// 記号・絵文字・英字略語
+// ----------------------------------------------------------------------
+
+var (
+	reMarkdownLink    = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	reMarkdownHeading = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+`)
+	reMarkdownList    = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+\.)\s+`)
+	reMarkdownQuote   = regexp.MustCompile(`(?m)^\s*>\s?`)
+	reHTMLTag         = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	reAcronym         = regexp.MustCompile(`\b[A-Z]{2,}\b`)
+	reSpaces          = regexp.MustCompile(`[ \t]{2,}`)
+)
+
+// reMarkdownEmph は強調・打ち消し線・インラインコードの記法です。対になった記号のみを対象とし、
+// "3*4" のような単独の記号は残します。
+var reMarkdownEmph = []*regexp.Regexp{
+	regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*`),
+	regexp.MustCompile(`__(\S(?:.*?\S)?)__`),
+	regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`),
+	regexp.MustCompile("`+([^`]+)`+"),
+}
+
+// reMarkdownItalic は "*強調*" の記法です。"2*3 と 4*5" のような数式を誤って取り除かないよう、
+// 直前が英数字の "*" は強調の開始として扱いません。reMarkdownEmph ("**") の後に適用します。
+var reMarkdownItalic = regexp.MustCompile(`(^|[^0-9A-Za-z*])\*(\S(?:.*?\S)?)\*`)
+
+// Markup はLLMの出力に残りがちなMarkdownの記法とHTMLタグを取り除きます。
+// リンクと画像は表示テキストのみを残します。
+func Markup() Normalizer {
+	return Func(func(text string) string {
+		text = reMarkdownLink.ReplaceAllString(text, "$1")
+		text = reHTMLTag.ReplaceAllString(text, "")
+		text = reMarkdownHeading.ReplaceAllString(text, "")
+		text = reMarkdownList.ReplaceAllString(text, "")
+		text = reMarkdownQuote.ReplaceAllString(text, "")
+		for _, re := range reMarkdownEmph {
+			text = re.ReplaceAllString(text, "$1")
+		}
+		text = reMarkdownItalic.ReplaceAllString(text, "${1}${2}")
+		return strings.TrimSpace(reSpaces.ReplaceAllString(text, " "))
+	})
+}
+
+// Emoji は絵文字と、絵文字の合成に使われる結合子・異体字セレクタを取り除きます。
+func Emoji() Normalizer {
+	return Func(func(text string) string {
+		text = strings.Map(func(r rune) rune {
+			if isEmoji(r) {
+				return -1
+			}
+			return r
+		}, text)
+		return strings.TrimSpace(reSpaces.ReplaceAllString(text, " "))
+	})
+}
+
+// isEmoji は r が絵文字または絵文字の修飾に使われる文字であるかを返します。
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // 絵文字、記号、国旗、肌の色修飾子など
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // その他の記号、装飾記号
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // 矢印・星などの記号
+		return true
+	case r == 0x200D || r == 0x20E3: // ゼロ幅接合子、囲み記号
+		return true
+	case r >= 0xFE00 && r <= 0xFE0F: // 異体字セレクタ
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // タグ文字
+		return true
+	}
+	return false
+}
+
+// Acronyms は大文字2文字以上の英字略語 (API, URL など) をカタカナの読みに変換します。
+// overrides には単語として読む略語 (例: "NASA": "ナサ") を指定でき、1文字ずつの読みより優先されます。
+func Acronyms(overrides map[string]string) Normalizer {
+	return Func(func(text string) string {
+		return reAcronym.ReplaceAllStringFunc(text, func(word string) string {
+			if reading, ok := overrides[word]; ok {
+				return reading
+			}
+			var b strings.Builder
+			for _, r := range word {
+				if reading, ok := letterReadings[unicode.ToUpper(r)]; ok {
+					b.WriteString(reading)
+				}
+			}
+			return b.String()
+		})
+	})
+}
@@ -3,6 +3,29 @@ package parser
 const (
 	// VOICEVOXが安全に処理できる最大文字数の目安。
 	MaxSegmentCharLength = 200
+	// 無音指定のパターン (数値と単位をキャプチャ)。例: [間:800ms], [間:1.5秒], [pause:500]
+	PauseDirectivePattern = `(?:間|ポーズ|pause)\s*[:：]\s*(\d+(?:\.\d+)?)\s*(ms|ミリ秒|s|秒)?`
 	// 正規表現で利用する感情タグのパターン
 	EmotionTagsPattern = `(解説|疑問|驚き|理解|落ち着き|納得|断定|呼びかけ|まとめ|通常|喜び|怒り|ノーマル|あまあま|ツンツン|セクシー|ヒソヒソ|ささやき)`
 )
+
+// プロソディ指定 ({speed=1.2 pitch=0.05}) で使用できる項目名
+const (
+	ProsodyKeySpeed      = "speed"
+	ProsodyKeyPitch      = "pitch"
+	ProsodyKeyIntonation = "intonation"
+	ProsodyKeyVolume     = "volume"
+)
+
+// コメント構文
+const (
+	BlockCommentStart = "/*"
+	BlockCommentEnd   = "*/"
+)
+
+// LineCommentPrefixes は行コメントとして扱う行頭の記号です。
+var LineCommentPrefixes = []string{"//"}
+
+// HashCommentPrefix は直後に空白が続く場合 (または単独の行) に限り行コメントとして扱う記号です。
+// "#1位は…" や "#タグ" のように本文が # で始まる行を誤って削除しないため、"# コメント" の形式のみを対象とします。
+const HashCommentPrefix = "#"
@@ -0,0 +1,13 @@
+package parser
+
+import "fmt"
+
+// ErrUnclosedBlockComment はブロックコメント ("/* ... */") が閉じられないままスクリプトが終わったことを示します。
+// 以降の行が黙って読み上げ対象から外れるのを防ぐため、Parse はこのエラーを返します。
+type ErrUnclosedBlockComment struct {
+	Line int // ブロックコメントが始まった行番号 (1始まり)
+}
+
+func (e *ErrUnclosedBlockComment) Error() string {
+	return fmt.Sprintf("%d行目のブロックコメント (%s) が閉じられていません (%s が必要です)", e.Line, BlockCommentStart, BlockCommentEnd)
+}
@@ -3,7 +3,10 @@ package parser
 import (
 	"log/slog"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 	"unicode/utf8"
 )
 
@@ -16,21 +19,57 @@ type Parser interface {
 // データモデル (スクリプト処理)
 // ----------------------------------------------------------------------
 
+// SegmentKind はセグメントの種類を表します。
+type SegmentKind int
+
+const (
+	// SegmentSpeech は音声合成の対象となるテキストセグメントです (ゼロ値)。
+	SegmentSpeech SegmentKind = iota
+	// SegmentSilence はAPIを呼び出さずに無音を挿入するセグメントです。
+	SegmentSilence
+)
+
 // Segment は解析されたスクリプトの一片を表す構造体です。
 // BaseSpeakerTag はスタイルタグを含まない話者名 ([ずんだもん]) を格納します。
+// Prosody はタグ直後のプロソディ指定 ({speed=1.2 pitch=0.05} など) から得られたセグメント単位の上書き設定です。
+// Engine が api.Prosody に変換し、エンジン全体・話者ごとの設定と重ね合わせます。
+// Kind が SegmentSilence の場合、タグとテキストは空で、Silence に無音の長さが格納されます。
 type Segment struct {
+	Kind           SegmentKind
 	SpeakerTag     string // 例: "[ずんだもん][ノーマル]"
 	BaseSpeakerTag string // 例: "[ずんだもん]"
 	Text           string
+	Prosody        Prosody
+	Silence        time.Duration
+}
+
+// Prosody はプロソディ指定で上書きする話速・音高・抑揚・音量です。nil のフィールドは指定されていないことを表します。
+// スクリプトの文法を API クライアントから独立させるため、api.Prosody と同じ構造の型を parser パッケージで定義しています。
+type Prosody struct {
+	SpeedScale      *float64
+	PitchScale      *float64
+	IntonationScale *float64
+	VolumeScale     *float64
+}
+
+// IsSilence はセグメントが無音セグメントである場合に true を返します。
+func (s Segment) IsSilence() bool {
+	return s.Kind == SegmentSilence
 }
 
 var (
 	// スクリプトの基本形式: [話者タグ][スタイルタグ] テキスト
 	reScriptParse = regexp.MustCompile(`^(\[.+?\])\s*(\[.+?\])\s*(.*)`)
+	// スタイルを省略した形式: [話者タグ] テキスト (話者のデフォルトスタイルで合成)
+	reSpeakerOnlyParse = regexp.MustCompile(`^(\[[^\[\]]+\])\s*(.*)`)
 	// テキストから感情タグを取り除くための正規表現
 	reEmotionParse = regexp.MustCompile(`\[` + EmotionTagsPattern + `\]`)
 	// BaseSpeakerTag 抽出のための正規表現: ^(\[.+?\])
 	reBaseSpeakerTag = regexp.MustCompile(`^(\[.+?\])`)
+	// タグ直後のプロソディ指定: {speed=1.2 pitch=0.05}
+	reProsodyDirective = regexp.MustCompile(`^\{([^}]*)\}\s*(.*)`)
+	// 無音指定: [間:800ms], [間:1.5s], [pause:500]
+	rePauseDirective = regexp.MustCompile(`\[` + PauseDirectivePattern + `\]`)
 )
 
 // ----------------------------------------------------------------------
@@ -38,48 +77,197 @@ var (
 // ----------------------------------------------------------------------
 
 // textParser はスクリプトの解析状態を管理し、セグメント化を実行します。
+// NewParser が返すインスタンスは設定のみを保持し、解析状態は Parse の呼び出しごとに新しく作成されます。
 type textParser struct {
-	segments    []Segment
-	currentTag  string
-	currentText *strings.Builder
-	textBuffer  string
-	fallbackTag string
+	segments       []Segment
+	currentTag     string
+	currentProsody Prosody
+	currentText    *strings.Builder
+	textBuffer     string
+	fallbackTag    string
+
+	blankLinePause   time.Duration
+	turnGap          time.Duration
+	pendingBlankLine bool
+}
+
+// Option は textParser の設定を行うための関数型です。
+type Option func(*textParser)
+
+// WithBlankLinePause は、空行を指定された長さの無音として扱うオプションです。
+// 連続する空行は一つの無音にまとめられ、スクリプト先頭・末尾の空行は無視されます。
+func WithBlankLinePause(d time.Duration) Option {
+	return func(p *textParser) {
+		p.blankLinePause = d
+	}
+}
+
+// WithTurnGap は、話者が切り替わる箇所に指定された長さの無音を挿入するオプションです。
+// 既に無音セグメントがある箇所には挿入しません。
+func WithTurnGap(d time.Duration) Option {
+	return func(p *textParser) {
+		p.turnGap = d
+	}
 }
 
 // NewParser は textParser インスタンスを生成し、Parser インターフェースとして返します。
-func NewParser() *textParser {
-	return &textParser{
+func NewParser(opts ...Option) *textParser {
+	p := &textParser{
 		currentText: &strings.Builder{},
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // Parse は Parser インターフェースのメソッド実装です。
+// 解析状態は呼び出しごとに作成するため、複数のゴルーチンから同時に呼び出すことができます。
+// ブロックコメントが閉じられていない場合は ErrUnclosedBlockComment を返します。
 func (p *textParser) Parse(scriptContent string, fallbackTag string) ([]Segment, error) {
-	p.fallbackTag = fallbackTag
-	p.segments = nil // 過去のセグメントをリセット
+	lines, err := stripComments(strings.Split(scriptContent, "\n"))
+	if err != nil {
+		return nil, err
+	}
 
-	lines := strings.Split(scriptContent, "\n")
+	state := &textParser{
+		currentText:    &strings.Builder{},
+		fallbackTag:    fallbackTag,
+		blankLinePause: p.blankLinePause,
+		turnGap:        p.turnGap,
+	}
+	return state.parse(lines), nil
+}
 
+// parse は新しく作成された解析状態で、コメントを取り除いた行をセグメントに分割します。
+func (p *textParser) parse(lines []string) []Segment {
 	for _, line := range lines {
 		trimmedLine := strings.TrimSpace(line)
 		if trimmedLine == "" {
+			// 空行による無音は、次の有効な行が現れた時点で確定する (末尾の空行を無視するため)
+			if p.blankLinePause > 0 && len(p.segments)+p.currentText.Len() > 0 {
+				p.pendingBlankLine = true
+			}
 			continue
 		}
+		if p.pendingBlankLine {
+			p.flushCurrentSegment()
+			p.addSilence(p.blankLinePause)
+			p.pendingBlankLine = false
+		}
 		p.processLine(trimmedLine)
 	}
 
 	p.finishParsing()
+	p.insertTurnGaps()
 
-	// エラー処理は内部でログ出力しているため、ここでは nil を返す設計を維持
-	return p.segments, nil
+	// エラー処理は内部でログ出力しているため、Parse はエラーを返さない設計を維持
+	return p.segments
 }
 
 // ----------------------------------------------------------------------
 // 内部処理ロジック
 // ----------------------------------------------------------------------
 
+// stripComments はスクリプトの各行からコメントを取り除きます。
+// 行コメント ("//" で始まる行、または "# " のように # の後に空白が続く行) とブロックコメント ("/* ... */") に対応します。
+// "/*" は、同じ行に対応する "*/" がある場合 (行内コメント) か、行頭にある場合 (複数行のブロックコメント) にのみ
+// コメントの開始として扱います。"src/*.go" のように本文中に現れる "/*" はそのまま残します。
+// コメントのみで構成される行は空行として扱わず、行ごと削除します (空行による無音指定と区別するため)。
+// 行頭から始まったブロックコメントが閉じられないままスクリプトが終わった場合は ErrUnclosedBlockComment を返します。
+func stripComments(lines []string) ([]string, error) {
+	result := make([]string, 0, len(lines))
+	inBlock := false
+	blockStartLine := 0
+
+	for lineNo, line := range lines {
+		var b strings.Builder
+		hadComment := inBlock
+		rest := line
+
+		for rest != "" {
+			if inBlock {
+				end := strings.Index(rest, BlockCommentEnd)
+				if end < 0 {
+					rest = ""
+					break
+				}
+				rest = rest[end+len(BlockCommentEnd):]
+				inBlock = false
+				continue
+			}
+
+			start := strings.Index(rest, BlockCommentStart)
+			if start < 0 {
+				b.WriteString(rest)
+				break
+			}
+			b.WriteString(rest[:start])
+			afterStart := rest[start+len(BlockCommentStart):]
+
+			switch end := strings.Index(afterStart, BlockCommentEnd); {
+			case end >= 0:
+				// 行内コメント: "/* ... */" を取り除き、続きを処理する
+				rest = afterStart[end+len(BlockCommentEnd):]
+				hadComment = true
+			case strings.TrimSpace(b.String()) == "":
+				// 行頭の "/*": 次の行以降の "*/" までをコメントとする
+				rest = ""
+				inBlock = true
+				blockStartLine = lineNo + 1
+				hadComment = true
+			default:
+				// 本文中の閉じられない "/*" はコメントとして扱わない
+				b.WriteString(BlockCommentStart)
+				rest = afterStart
+			}
+		}
+
+		stripped := b.String()
+		trimmed := strings.TrimSpace(stripped)
+		if trimmed == "" && hadComment {
+			continue
+		}
+		if isLineComment(trimmed) {
+			continue
+		}
+		result = append(result, stripped)
+	}
+
+	if inBlock {
+		return nil, &ErrUnclosedBlockComment{Line: blockStartLine}
+	}
+	return result, nil
+}
+
+// isLineComment は行が行コメントであるかを判定します。
+// LineCommentPrefixes で始まる行、または HashCommentPrefix の直後が空白か行末である行をコメントとして扱います。
+func isLineComment(trimmedLine string) bool {
+	for _, prefix := range LineCommentPrefixes {
+		if strings.HasPrefix(trimmedLine, prefix) {
+			return true
+		}
+	}
+	if rest, ok := strings.CutPrefix(trimmedLine, HashCommentPrefix); ok {
+		r, _ := utf8.DecodeRuneInString(rest)
+		return rest == "" || unicode.IsSpace(r)
+	}
+	return false
+}
+
 // processLine はスクリプトの1行を処理します。
 func (p *textParser) processLine(line string) {
+	// 行頭の無音指定を処理 (例: "[間:800ms]" のみの行)
+	for {
+		loc := rePauseDirective.FindStringSubmatchIndex(line)
+		if loc == nil || loc[0] != 0 {
+			break
+		}
+		p.flushCurrentSegment()
+		p.addSilence(pauseDurationAt(line, loc))
+		line = strings.TrimSpace(line[loc[1]:])
+	}
+
 	if line == "" {
 		return
 	}
@@ -91,16 +279,25 @@ func (p *textParser) processLine(line string) {
 		p.textBuffer = ""
 	}
 
+	// 2つ目の [..] が無音指定 ("[ナレーター] [間:500ms] 続く") の場合はスタイルタグとして扱わず、
+	// 話者タグのみの行として本文中の無音指定を処理する
 	matches := reScriptParse.FindStringSubmatch(textToProcess)
-	if len(matches) > 3 {
+	if len(matches) > 3 && !rePauseDirective.MatchString(matches[2]) {
 		speakerTag := matches[1] // 例: [ずんだもん]
 		vvStyleTag := matches[2] // 例: [ノーマル]
 		textPart := matches[3]
 		newCombinedTag := speakerTag + vvStyleTag // 例: [ずんだもん][ノーマル]
 		p.processTaggedLine(newCombinedTag, textPart)
-	} else {
-		p.processUntaggedLine(textToProcess)
+		return
+	}
+
+	// スタイルを省略した "[ナレーター] テキスト" は話者タグのみを SpeakerTag とし、
+	// Style ID の解決時に話者 (別名) のデフォルトスタイルを使用する。感情タグで始まる行は対象外
+	if matches := reSpeakerOnlyParse.FindStringSubmatch(textToProcess); len(matches) > 2 && !reEmotionParse.MatchString(matches[1]) {
+		p.processTaggedLine(matches[1], matches[2])
+		return
 	}
+	p.processUntaggedLine(textToProcess)
 }
 
 // processTaggedLine はタグ付きの行を処理します。
@@ -111,13 +308,58 @@ func (p *textParser) processTaggedLine(tag, text string) {
 	}
 
 	p.currentTag = tag
-	p.appendAndSplitText(text)
+	p.currentProsody = Prosody{}
+	if m := reProsodyDirective.FindStringSubmatch(text); len(m) > 2 {
+		p.currentProsody = parseProsody(m[1])
+		text = m[2]
+	}
+	p.appendText(text)
+}
+
+// parseProsody は "speed=1.2 pitch=0.05" 形式のプロソディ指定を解析します。
+// 区切りには空白またはカンマを使用でき、不正な指定は警告を出して無視します。
+func parseProsody(directive string) Prosody {
+	var prosody Prosody
+	fields := strings.FieldsFunc(directive, func(r rune) bool {
+		return r == ',' || r == '、' || r == ' ' || r == '\t' || r == '　'
+	})
+
+	for _, field := range fields {
+		key, rawValue, found := strings.Cut(field, "=")
+		if !found {
+			key, rawValue, found = strings.Cut(field, ":")
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(rawValue), 64)
+		if !found || err != nil {
+			slog.Warn("不正なプロソディ指定を無視します。", "directive", field)
+			continue
+		}
+
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case ProsodyKeySpeed, "話速":
+			prosody.SpeedScale = floatPtr(value)
+		case ProsodyKeyPitch, "音高":
+			prosody.PitchScale = floatPtr(value)
+		case ProsodyKeyIntonation, "抑揚":
+			prosody.IntonationScale = floatPtr(value)
+		case ProsodyKeyVolume, "音量":
+			prosody.VolumeScale = floatPtr(value)
+		default:
+			slog.Warn("未知のプロソディ項目を無視します。", "key", key)
+		}
+	}
+	return prosody
+}
+
+// floatPtr は value へのポインタを返します。
+func floatPtr(value float64) *float64 {
+	return &value
 }
 
 // processUntaggedLine はタグのない行を処理します。
 func (p *textParser) processUntaggedLine(text string) {
 	if p.currentTag != "" {
-		p.appendAndSplitText(text)
+		p.appendText(text)
 	} else {
 		// タグなしの行をバッファリングし、次のタグ付きセグメントに結合
 		p.textBuffer = text
@@ -125,6 +367,71 @@ func (p *textParser) processUntaggedLine(text string) {
 	}
 }
 
+// appendText はテキスト中の無音指定でセグメントを区切りながら、テキストを現在のセグメントに追記します。
+func (p *textParser) appendText(text string) {
+	for {
+		loc := rePauseDirective.FindStringSubmatchIndex(text)
+		if loc == nil {
+			p.appendAndSplitText(text)
+			return
+		}
+
+		p.appendAndSplitText(strings.TrimSpace(text[:loc[0]]))
+		p.flushCurrentSegment()
+		p.addSilence(pauseDurationAt(text, loc))
+		text = strings.TrimSpace(text[loc[1]:])
+	}
+}
+
+// pauseDurationAt は rePauseDirective のマッチ位置 loc から無音の長さを算出します。単位の省略時はミリ秒として扱います。
+func pauseDurationAt(s string, loc []int) time.Duration {
+	value := s[loc[2]:loc[3]]
+	unit := ""
+	if loc[4] >= 0 {
+		unit = s[loc[4]:loc[5]]
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		slog.Warn("不正な無音指定を無視します。", "value", value)
+		return 0
+	}
+
+	switch unit {
+	case "s", "秒":
+		return time.Duration(v * float64(time.Second))
+	default:
+		return time.Duration(v * float64(time.Millisecond))
+	}
+}
+
+// addSilence は無音セグメントをリストに追加します。
+func (p *textParser) addSilence(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	p.segments = append(p.segments, Segment{Kind: SegmentSilence, Silence: d})
+}
+
+// insertTurnGaps は話者が切り替わる箇所に turnGap の長さの無音セグメントを挿入します。
+func (p *textParser) insertTurnGaps() {
+	if p.turnGap <= 0 || len(p.segments) < 2 {
+		return
+	}
+
+	result := make([]Segment, 0, len(p.segments))
+	for i, seg := range p.segments {
+		if i > 0 && !seg.IsSilence() {
+			prev := p.segments[i-1]
+			if !prev.IsSilence() && prev.BaseSpeakerTag != seg.BaseSpeakerTag {
+				result = append(result, Segment{Kind: SegmentSilence, Silence: p.turnGap})
+			}
+		}
+		result = append(result, seg)
+	}
+	p.segments = result
+}
+
 // appendAndSplitText はテキストを現在のセグメントに追記し、必要に応じて分割します。
 func (p *textParser) appendAndSplitText(text string) {
 	textToAppend := text
@@ -216,8 +523,9 @@ func (p *textParser) flushCurrentSegment() {
 
 // addSegment は整形後のテキストからセグメントを作成し、リストに追加します。
 func (p *textParser) addSegment(tag string, text string) {
-	// 感情タグを削除し、トリム
+	// 感情タグと (バッファ経由で残った) 無音指定を削除し、トリム
 	finalText := reEmotionParse.ReplaceAllString(text, "")
+	finalText = rePauseDirective.ReplaceAllString(finalText, "")
 	finalText = strings.TrimSpace(finalText)
 
 	if finalText != "" {
@@ -236,6 +544,7 @@ func (p *textParser) addSegment(tag string, text string) {
 			SpeakerTag:     tag,
 			BaseSpeakerTag: baseTag,
 			Text:           finalText,
+			Prosody:        p.currentProsody,
 		})
 	}
 }
@@ -245,9 +554,9 @@ func (p *textParser) finishParsing() {
 	p.flushCurrentSegment()
 
 	if p.textBuffer != "" {
-		if len(p.segments) > 0 {
+		if p.lastSpeakerTag() != "" {
 			// 既存のセグメントがある場合、最後のタグを流用
-			lastTag := p.segments[len(p.segments)-1].SpeakerTag
+			lastTag := p.lastSpeakerTag()
 			slog.Warn("スクリプトの最後にタグのないテキストが残りました。最後のタグを流用して最終セグメントとして合成します。",
 				"lost_text", p.textBuffer, "used_tag", lastTag)
 			p.addSegment(lastTag, p.textBuffer)
@@ -263,3 +572,13 @@ func (p *textParser) finishParsing() {
 		}
 	}
 }
+
+// lastSpeakerTag は最後の音声セグメントの話者タグを返します。音声セグメントがない場合は空文字列を返します。
+func (p *textParser) lastSpeakerTag() string {
+	for i := len(p.segments) - 1; i >= 0; i-- {
+		if !p.segments[i].IsSilence() {
+			return p.segments[i].SpeakerTag
+		}
+	}
+	return ""
+}
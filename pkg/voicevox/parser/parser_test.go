@@ -0,0 +1,284 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestParseConcurrent は同じ Parser を複数のゴルーチンから同時に呼び出しても、結果が混ざらないことを確認します。
+func TestParseConcurrent(t *testing.T) {
+	p := NewParser()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			text := strings.Repeat("あ", i+1)
+			for range 50 {
+				segments, err := p.Parse("[ずんだもん][ノーマル] "+text, "")
+				if err != nil || len(segments) != 1 || segments[0].Text != text {
+					t.Errorf("Parse = %+v, %v; want one segment %q", segments, err, text)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// segmentSummary はセグメントを "SpeakerTag|BaseSpeakerTag|Text" 形式で表します (無音は "silence:800ms")。
+func segmentSummary(segments []Segment) []string {
+	var got []string
+	for _, seg := range segments {
+		if seg.IsSilence() {
+			got = append(got, "silence:"+seg.Silence.String())
+			continue
+		}
+		got = append(got, seg.SpeakerTag+"|"+seg.BaseSpeakerTag+"|"+seg.Text)
+	}
+	return got
+}
+
+func TestParseSpeakerTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "話者タグとスタイルタグ",
+			script: "[ずんだもん][ノーマル] こんにちは",
+			want:   []string{"[ずんだもん][ノーマル]|[ずんだもん]|こんにちは"},
+		},
+		{
+			name:   "話者タグのみ (スタイルは省略)",
+			script: "[ナレーター] 物語の始まりです。",
+			want:   []string{"[ナレーター]|[ナレーター]|物語の始まりです。"},
+		},
+		{
+			name:   "話者タグのみとプロソディ指定",
+			script: "[ナレーター]{speed=1.2} ゆっくり話します。",
+			want:   []string{"[ナレーター]|[ナレーター]|ゆっくり話します。"},
+		},
+		{
+			name:   "感情タグで始まる行は前のセグメントに続く",
+			script: "[ずんだもん][ノーマル] こんにちは\n[解説]補足です。",
+			want:   []string{"[ずんだもん][ノーマル]|[ずんだもん]|こんにちは 補足です。"},
+		},
+		{
+			name:   "話者タグのみの行で話者が切り替わる",
+			script: "[ずんだもん][ノーマル] こんにちは\n[ナレーター] 場面は変わり",
+			want: []string{
+				"[ずんだもん][ノーマル]|[ずんだもん]|こんにちは",
+				"[ナレーター]|[ナレーター]|場面は変わり",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := NewParser().Parse(tt.script, "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := segmentSummary(segments); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Parse(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLineComment(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{line: "// コメント", want: true},
+		{line: "//コメント", want: true},
+		{line: "# コメント", want: true},
+		{line: "#\tタブ区切りのコメント", want: true},
+		{line: "#　全角空白のコメント", want: true},
+		{line: "#", want: true},
+		{line: "#1位は東京です。", want: false},
+		{line: "#タグ付きの本文", want: false},
+		{line: "[ずんだもん][ノーマル] # は本文", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := isLineComment(tt.line); got != tt.want {
+			t.Errorf("isLineComment(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	const zunda, metan = "[ずんだもん][ノーマル]", "[めたん][ノーマル]"
+	tests := []struct {
+		name     string
+		script   string
+		want     []string
+		wantLine int // ErrUnclosedBlockComment の行番号 (0 はエラーなし)
+	}{
+		{
+			name:   "行内のブロックコメント",
+			script: zunda + " こんにちは /* 補足 */ なのだ",
+			want:   []string{zunda + "|[ずんだもん]|こんにちは  なのだ"},
+		},
+		{
+			name:   "複数行のブロックコメント",
+			script: zunda + " 一行目\n/* ここから\n" + metan + " 読まない\nここまで */\n" + metan + " 二行目",
+			want:   []string{zunda + "|[ずんだもん]|一行目", metan + "|[めたん]|二行目"},
+		},
+		{
+			name:   "本文中のパスに含まれる /* はコメントではない",
+			script: zunda + " ファイルは src/*.go にあります\n" + metan + " 次の行です",
+			want:   []string{zunda + "|[ずんだもん]|ファイルは src/*.go にあります", metan + "|[めたん]|次の行です"},
+		},
+		{
+			name:     "閉じられないブロックコメントはエラー",
+			script:   zunda + " 一行目\n/* 閉じ忘れ\n" + metan + " 二行目",
+			wantLine: 2,
+		},
+		{
+			name:   "行コメント",
+			script: "// 前書き\n# メモ\n" + zunda + " 本文です",
+			want:   []string{zunda + "|[ずんだもん]|本文です"},
+		},
+		{
+			name:   "空白が続かない # は本文",
+			script: zunda + " 今日のランキングです。\n#1位は東京です。",
+			want:   []string{zunda + "|[ずんだもん]|今日のランキングです。 #1位は東京です。"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := NewParser().Parse(tt.script, "")
+			if tt.wantLine > 0 {
+				var commentErr *ErrUnclosedBlockComment
+				if !errors.As(err, &commentErr) || commentErr.Line != tt.wantLine {
+					t.Fatalf("Parse error = %v, want ErrUnclosedBlockComment at line %d", err, tt.wantLine)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := segmentSummary(segments); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Parse(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePauses(t *testing.T) {
+	const zunda, metan = "[ずんだもん][ノーマル]", "[めたん][ノーマル]"
+	tests := []struct {
+		name   string
+		opts   []Option
+		script string
+		want   []string
+	}{
+		{
+			name:   "ミリ秒・秒・単位の省略",
+			script: zunda + " 一[間:800ms]二[間:1.5秒]三[pause:500]四[ポーズ：2s]五",
+			want: []string{
+				zunda + "|[ずんだもん]|一", "silence:800ms",
+				zunda + "|[ずんだもん]|二", "silence:1.5s",
+				zunda + "|[ずんだもん]|三", "silence:500ms",
+				zunda + "|[ずんだもん]|四", "silence:2s",
+				zunda + "|[ずんだもん]|五",
+			},
+		},
+		{
+			name:   "行頭の無音指定",
+			script: zunda + " 一行目\n[間:300ms]\n" + zunda + " 二行目",
+			want:   []string{zunda + "|[ずんだもん]|一行目", "silence:300ms", zunda + "|[ずんだもん]|二行目"},
+		},
+		{
+			name:   "話者タグの直後の無音指定はスタイルタグではない",
+			script: "[ナレーター] [間:500ms] 続く",
+			want:   []string{"silence:500ms", "[ナレーター]|[ナレーター]|続く"},
+		},
+		{
+			name:   "空行の無音 (連続する空行と先頭・末尾の空行はまとめる)",
+			opts:   []Option{WithBlankLinePause(700 * time.Millisecond)},
+			script: "\n" + zunda + " 一段落目\n\n\n" + zunda + " 二段落目\n\n",
+			want:   []string{zunda + "|[ずんだもん]|一段落目", "silence:700ms", zunda + "|[ずんだもん]|二段落目"},
+		},
+		{
+			name:   "話者交代の間",
+			opts:   []Option{WithTurnGap(200 * time.Millisecond)},
+			script: zunda + " 一\n" + zunda + " 二\n" + metan + " 三\n[間:1s]\n" + zunda + " 四",
+			want: []string{
+				zunda + "|[ずんだもん]|一", zunda + "|[ずんだもん]|二",
+				"silence:200ms", metan + "|[めたん]|三",
+				"silence:1s", zunda + "|[ずんだもん]|四",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := NewParser(tt.opts...).Parse(tt.script, "")
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if got := segmentSummary(segments); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Parse(%q) = %q, want %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProsody(t *testing.T) {
+	f := floatPtr
+	tests := []struct {
+		name      string
+		directive string
+		want      Prosody
+	}{
+		{name: "すべての項目", directive: "speed=1.2 pitch=0.05 intonation=1.1 volume=0.9", want: Prosody{SpeedScale: f(1.2), PitchScale: f(0.05), IntonationScale: f(1.1), VolumeScale: f(0.9)}},
+		{name: "日本語の項目名とコロン・読点区切り", directive: "話速:1.5、音量:0.8", want: Prosody{SpeedScale: f(1.5), VolumeScale: f(0.8)}},
+		{name: "大文字の項目名とカンマ区切り", directive: "Speed=0.9,PITCH=-0.1", want: Prosody{SpeedScale: f(0.9), PitchScale: f(-0.1)}},
+		{name: "数値でない値は無視", directive: "speed=fast pitch=0.1", want: Prosody{PitchScale: f(0.1)}},
+		{name: "値のない指定は無視", directive: "speed volume=1", want: Prosody{VolumeScale: f(1)}},
+		{name: "未知の項目は無視", directive: "tempo=2 speed=1.1", want: Prosody{SpeedScale: f(1.1)}},
+		{name: "空の指定", directive: "", want: Prosody{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseProsody(tt.directive); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProsody(%q) = %s, want %s", tt.directive, formatProsody(got), formatProsody(tt.want))
+			}
+		})
+	}
+
+	segments, err := NewParser().Parse("[ずんだもん][ノーマル]{speed=1.2} 早口です", "")
+	if err != nil || len(segments) != 1 || !reflect.DeepEqual(segments[0].Prosody, Prosody{SpeedScale: f(1.2)}) {
+		t.Errorf("Parse のプロソディ = %+v, %v; want speed=1.2", segments, err)
+	}
+}
+
+// formatProsody はテストの失敗時に表示するため、Prosody を "speed=1.2 pitch=<nil> ..." の形式で表します。
+func formatProsody(p Prosody) string {
+	var b strings.Builder
+	for _, field := range []struct {
+		key   string
+		value *float64
+	}{{"speed", p.SpeedScale}, {"pitch", p.PitchScale}, {"intonation", p.IntonationScale}, {"volume", p.VolumeScale}} {
+		if field.value == nil {
+			b.WriteString(field.key + "=<nil> ")
+			continue
+		}
+		b.WriteString(field.key + "=" + strconv.FormatFloat(*field.value, 'g', -1, 64) + " ")
+	}
+	return strings.TrimSpace(b.String())
+}
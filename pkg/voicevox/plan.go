@@ -0,0 +1,53 @@
+package voicevox
+
+import (
+	"context"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// 合成計画 (APIを呼び出さない事前確認)
+// ----------------------------------------------------------------------
+
+// Plan はスクリプトを解析し、Style ID の解決と読み上げ用テキストの前処理までを行った結果を返します。
+// /audio_query や /synthesis は呼び出さないため、スクリプトの検証や合成前の確認に利用できます。
+// SegmentInfo の Start / End はテキストの文字数と話速から推定した値です。
+// Style ID の解決や前処理に失敗したセグメントがある場合は、SegmentInfo とともに ErrSynthesisBatch を返します。
+func (e *Engine) Plan(ctx context.Context, scriptContent string, opts ...ExecuteOption) ([]SegmentInfo, error) {
+	cfg := newExecuteConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	segments, preCalcErrors, err := e.prepareSegments(ctx, scriptContent, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]SegmentInfo, len(segments))
+	var position time.Duration
+	for i, seg := range segments {
+		duration := seg.Silence
+		if !seg.IsSilence() {
+			duration = estimateSpeechDuration(seg)
+		}
+
+		infos[i] = SegmentInfo{
+			Index:      i,
+			Kind:       seg.Kind,
+			SpeakerTag: seg.SpeakerTag,
+			Text:       seg.Text,
+			SpeechText: seg.SpeechText,
+			StyleID:    seg.StyleID,
+			Start:      position,
+			End:        position + duration,
+			Skipped:    seg.Err != nil,
+		}
+		position += duration
+	}
+
+	if len(preCalcErrors) > 0 {
+		return infos, newSynthesisBatchError(preCalcErrors)
+	}
+	return infos, nil
+}
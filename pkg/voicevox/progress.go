@@ -0,0 +1,145 @@
+package voicevox
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/audio"
+)
+
+// ----------------------------------------------------------------------
+// 進捗イベント
+// ----------------------------------------------------------------------
+
+// ProgressEventType は進捗イベントの種類です。
+type ProgressEventType string
+
+const (
+	ProgressSegmentStarted  ProgressEventType = "segment_started"  // セグメントの処理を開始した
+	ProgressSegmentFinished ProgressEventType = "segment_finished" // セグメントの合成に成功した (キャッシュヒットを含む)
+	ProgressSegmentFailed   ProgressEventType = "segment_failed"   // セグメントの合成に失敗した
+	ProgressSegmentRetried  ProgressEventType = "segment_retried"  // 失敗したセグメントを再試行する
+	ProgressBatchComplete   ProgressEventType = "batch_complete"   // すべてのセグメントの処理が終了した
+)
+
+// ProgressEvent は音声合成バッチ処理の進捗を表します。
+// Total・Completed・Failed・Elapsed はイベントの発生時点のバッチ全体の集計です。
+type ProgressEvent struct {
+	Type ProgressEventType
+	// Index はセグメントのインデックスです。ProgressBatchComplete では -1 になります。
+	Index      int
+	SpeakerTag string
+
+	// Total はAPIで合成する (またはキャッシュから取得する) セグメントの数です。無音や事前計算で失敗したセグメントは含みません。
+	Total int
+	// Completed は処理が終了した (成功または失敗した) セグメントの数です。
+	Completed int
+	Failed    int
+	// Elapsed はバッチ処理の開始からの経過時間です。
+	Elapsed time.Duration
+
+	// SegmentElapsed はセグメントの処理にかかった時間です (ProgressSegmentFinished / ProgressSegmentFailed)。
+	SegmentElapsed time.Duration
+	// AudioDuration と Bytes は合成したWAVの再生時間とバイト数です (ProgressSegmentFinished)。
+	AudioDuration time.Duration
+	Bytes         int
+	CacheHit      bool
+
+	// Attempt は次に行う試行の回数 (2 回目以降) です (ProgressSegmentRetried)。
+	Attempt int
+	// Err は失敗の原因です (ProgressSegmentFailed / ProgressSegmentRetried)。
+	Err error
+}
+
+// Remaining は終了したセグメントの平均処理時間から、残りのセグメントの処理にかかる時間を推定します。
+// 終了したセグメントがない場合は 0 を返します。
+func (ev ProgressEvent) Remaining() time.Duration {
+	if ev.Completed == 0 || ev.Completed >= ev.Total {
+		return 0
+	}
+	return ev.Elapsed / time.Duration(ev.Completed) * time.Duration(ev.Total-ev.Completed)
+}
+
+// ProgressFunc は進捗イベントを受け取る関数です。
+// 1回のバッチ処理の中では、イベントは発生順に1つずつ (並行せずに) 渡されます。
+// 関数の実行中は他のセグメントの進捗の通知が待たされるため、重い処理は避けてください。
+type ProgressFunc func(ProgressEvent)
+
+// ----------------------------------------------------------------------
+// 進捗の集計 (engine.go で利用)
+// ----------------------------------------------------------------------
+
+// progressReporter はバッチ処理の進捗を集計し、ProgressFunc に通知します。
+// nil の場合は何もしません。
+type progressReporter struct {
+	mu        sync.Mutex
+	fn        ProgressFunc
+	start     time.Time
+	total     int
+	completed int
+	failed    int
+}
+
+// newProgressReporter は fn が設定されている場合のみ progressReporter を作成します。
+func newProgressReporter(fn ProgressFunc, total int) *progressReporter {
+	if fn == nil {
+		return nil
+	}
+	return &progressReporter{fn: fn, start: time.Now(), total: total}
+}
+
+// emit は集計値を付与してイベントを通知します。
+func (r *progressReporter) emit(ev ProgressEvent) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch ev.Type {
+	case ProgressSegmentFinished:
+		r.completed++
+	case ProgressSegmentFailed:
+		r.completed++
+		r.failed++
+	}
+	ev.Total, ev.Completed, ev.Failed = r.total, r.completed, r.failed
+	ev.Elapsed = time.Since(r.start)
+	r.fn(ev)
+}
+
+// started はセグメントの処理の開始を通知します。
+func (r *progressReporter) started(seg engineSegment, index int) {
+	r.emit(ProgressEvent{Type: ProgressSegmentStarted, Index: index, SpeakerTag: seg.SpeakerTag})
+}
+
+// retried はセグメントの再試行を通知します。attempt は次に行う試行の回数です。
+func (r *progressReporter) retried(seg engineSegment, index int, attempt int, err error) {
+	r.emit(ProgressEvent{Type: ProgressSegmentRetried, Index: index, SpeakerTag: seg.SpeakerTag, Attempt: attempt, Err: err})
+}
+
+// finished はセグメントの処理結果 (成功または失敗) を通知します。
+func (r *progressReporter) finished(seg engineSegment, result segmentResult, elapsed time.Duration) {
+	if r == nil {
+		return
+	}
+
+	ev := ProgressEvent{Index: result.index, SpeakerTag: seg.SpeakerTag, SegmentElapsed: elapsed}
+	if result.err != nil {
+		ev.Type = ProgressSegmentFailed
+		ev.Err = result.err
+	} else {
+		ev.Type = ProgressSegmentFinished
+		ev.Bytes = len(result.wavData)
+		ev.CacheHit = result.cacheHit
+		// WAVデータは processSegment で検証済み
+		ev.AudioDuration, _ = audio.WavDuration(result.wavData)
+	}
+	r.emit(ev)
+}
+
+// complete はバッチ処理の終了を通知します。
+func (r *progressReporter) complete() {
+	r.emit(ProgressEvent{Type: ProgressBatchComplete, Index: -1})
+}
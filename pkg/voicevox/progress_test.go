@@ -0,0 +1,121 @@
+package voicevox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// TestProgressEvents は並列に処理したセグメントの進捗イベントが、セグメントごとに発生順に並び、
+// 集計値が最後の ProgressBatchComplete で合計と一致し、コールバックが並行して呼ばれないことを確認します。
+func TestProgressEvents(t *testing.T) {
+	const script = "[ずんだもん][ノーマル] 一つ目\n[ずんだもん][ささやき] 二つ目\n[間:100ms]\n[ずんだもん][ノーマル] 三つ目"
+
+	tests := []struct {
+		name       string
+		config     EngineConfig
+		fail       func(text string, attempt int) error
+		want       map[int][]ProgressEventType
+		wantFailed int
+	}{
+		{
+			name: "成功と失敗",
+			fail: func(text string, attempt int) error {
+				if text == "三つ目" {
+					return errors.New("synthesis failed")
+				}
+				return nil
+			},
+			want: map[int][]ProgressEventType{
+				0: {ProgressSegmentStarted, ProgressSegmentFinished},
+				1: {ProgressSegmentStarted, ProgressSegmentFinished},
+				3: {ProgressSegmentStarted, ProgressSegmentFailed},
+			},
+			wantFailed: 1,
+		},
+		{
+			name:   "再試行",
+			config: EngineConfig{Retry: RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}},
+			fail: func(text string, attempt int) error {
+				if text == "二つ目" && attempt == 1 {
+					return &api.ErrAPIResponse{StatusCode: 503}
+				}
+				return nil
+			},
+			want: map[int][]ProgressEventType{
+				0: {ProgressSegmentStarted, ProgressSegmentFinished},
+				1: {ProgressSegmentStarted, ProgressSegmentRetried, ProgressSegmentFinished},
+				3: {ProgressSegmentStarted, ProgressSegmentFinished},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				inCallback atomic.Int32
+				overlapped atomic.Bool
+				events     []ProgressEvent
+			)
+			progress := func(ev ProgressEvent) {
+				if inCallback.Add(1) > 1 {
+					overlapped.Store(true)
+				}
+				// 並行して呼び出された場合に重なりを検出しやすくする
+				time.Sleep(time.Millisecond)
+				events = append(events, ev)
+				inCallback.Add(-1)
+			}
+
+			config := tt.config
+			config.MaxParallelSegments = 3
+			e := newFakeEngine(&fakeClient{fail: tt.fail}, config)
+			_, _ = e.Synthesize(context.Background(), script, WithProgress(progress))
+
+			if overlapped.Load() {
+				t.Error("進捗のコールバックが並行して呼び出されました")
+			}
+			if len(events) == 0 {
+				t.Fatal("進捗イベントがありません")
+			}
+
+			got := make(map[int][]ProgressEventType)
+			completed := 0
+			for _, ev := range events[:len(events)-1] {
+				got[ev.Index] = append(got[ev.Index], ev.Type)
+				if ev.Completed < completed {
+					t.Errorf("Completed が減少しました: %d -> %d", completed, ev.Completed)
+				}
+				completed = ev.Completed
+				if ev.Type == ProgressSegmentFinished && (ev.AudioDuration != 100*time.Millisecond || ev.Bytes == 0) {
+					t.Errorf("セグメント %d の AudioDuration = %v, Bytes = %d; want 100ms のWAV", ev.Index, ev.AudioDuration, ev.Bytes)
+				}
+				if ev.Type == ProgressSegmentRetried && (ev.Attempt != 2 || ev.Err == nil) {
+					t.Errorf("セグメント %d の再試行イベント = %+v, want Attempt 2 と Err", ev.Index, ev)
+				}
+				if ev.Type == ProgressSegmentFailed && ev.Err == nil {
+					t.Errorf("セグメント %d の失敗イベントに Err がありません", ev.Index)
+				}
+			}
+			for index, want := range tt.want {
+				if !slices.Equal(got[index], want) {
+					t.Errorf("セグメント %d のイベント = %v, want %v", index, got[index], want)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("イベントのあるセグメント = %d, want %d (%v)", len(got), len(tt.want), got)
+			}
+
+			last := events[len(events)-1]
+			total := len(tt.want)
+			if last.Type != ProgressBatchComplete || last.Index != -1 || last.Total != total || last.Completed != total || last.Failed != tt.wantFailed {
+				t.Errorf("最後のイベント = %+v, want %s (Total %d, Completed %d, Failed %d)", last, ProgressBatchComplete, total, total, tt.wantFailed)
+			}
+		})
+	}
+}
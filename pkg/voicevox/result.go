@@ -0,0 +1,164 @@
+package voicevox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/audio"
+	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
+)
+
+// ----------------------------------------------------------------------
+// 結果の集約
+// ----------------------------------------------------------------------
+
+// assembleResult はバッチ結果を集約し、WAVデータと無音区間を結合して Result を構築します。
+// AllowPartial が有効な場合は失敗したセグメントをスキップ (または無音で置換) し、ErrPartialSynthesis を返します。
+func (e *Engine) assembleResult(ctx context.Context, cfg *ExecuteConfig, segments []engineSegment, results []segmentResult, preCalcErrors []*SegmentError, runtimeErrors []*SegmentError) (*Result, error) {
+	allErrors := append([]*SegmentError{}, preCalcErrors...)
+	allErrors = append(allErrors, runtimeErrors...)
+
+	var batchErr *ErrSynthesisBatch
+	if len(allErrors) > 0 {
+		batchErr = newSynthesisBatchError(allErrors)
+		if !cfg.AllowPartial {
+			return nil, batchErr
+		}
+	}
+
+	infos := make([]SegmentInfo, len(segments))
+	clips := make([]audio.Clip, 0, len(segments))
+	// clipSegments は clips の各要素に対応するセグメントのインデックスです
+	clipSegments := make([]int, 0, len(segments))
+	hasAudio := false
+	var skippedIndices []int
+
+	for i, seg := range segments {
+		infos[i] = SegmentInfo{
+			Index:      i,
+			Kind:       seg.Kind,
+			SpeakerTag: seg.SpeakerTag,
+			Text:       seg.Text,
+			SpeechText: seg.SpeechText,
+			StyleID:    seg.StyleID,
+			CacheHit:   results[i].cacheHit,
+		}
+
+		switch {
+		case seg.IsSilence():
+			clips = append(clips, audio.Clip{Silence: seg.Silence})
+		case results[i].wavData != nil:
+			clips = append(clips, audio.Clip{WAV: results[i].wavData})
+			hasAudio = true
+		case seg.Text != "":
+			// 合成に失敗したセグメント (AllowPartial 時のみ到達)
+			infos[i].Skipped = true
+			skippedIndices = append(skippedIndices, i)
+			if !cfg.FillFailedWithSilence {
+				continue
+			}
+			clips = append(clips, audio.Clip{Silence: estimateSpeechDuration(seg)})
+		default:
+			continue
+		}
+		clipSegments = append(clipSegments, i)
+	}
+
+	if !hasAudio {
+		return nil, fmt.Errorf("すべてのセグメントの合成に失敗したか、有効なセグメントがありませんでした")
+	}
+
+	// 話者やエンジン設定によってフォーマットが異なる場合は、出力フォーマットに変換して結合する
+	combinedWavBytes, timeline, err := audio.CombineClipsWithTimeline(clips,
+		audio.WithOutputFormat(cfg.OutputSampleRate, cfg.OutputChannels))
+	if err != nil {
+		return nil, fmt.Errorf("WAVデータの結合に失敗しました: %w", err)
+	}
+
+	// タイムラインをセグメントに反映し、字幕キューを作成
+	result := &Result{WAV: combinedWavBytes, Segments: infos}
+	for clipIndex, span := range timeline {
+		info := &infos[clipSegments[clipIndex]]
+		info.Start, info.End = span.Start, span.End
+		result.Duration = span.End
+
+		if info.Kind == parser.SegmentSpeech && !info.Skipped {
+			result.Cues = append(result.Cues, subtitle.Cue{
+				Start:   span.Start,
+				End:     span.End,
+				Speaker: speakerDisplayName(segments[info.Index].BaseSpeakerTag),
+				Text:    info.Text,
+			})
+		}
+	}
+
+	if len(skippedIndices) > 0 {
+		slog.WarnContext(ctx, "一部のセグメントをスキップして音声を結合しました。",
+			"skipped_indices", skippedIndices,
+			"filled_with_silence", cfg.FillFailedWithSilence)
+		return result, &ErrPartialSynthesis{
+			SkippedIndices:    skippedIndices,
+			FilledWithSilence: cfg.FillFailedWithSilence,
+			Batch:             batchErr,
+		}
+	}
+
+	return result, nil
+}
+
+// estimateSpeechDuration はテキストの文字数と話速から、セグメントの音声のおおよその長さを推定します。
+func estimateSpeechDuration(seg engineSegment) time.Duration {
+	estimated := time.Duration(utf8.RuneCountInString(seg.Text)) * EstimatedDurationPerChar
+	if speed := seg.ResolvedProsody.SpeedScale; speed != nil && *speed > 0 {
+		estimated = time.Duration(float64(estimated) / *speed)
+	}
+	return estimated
+}
+
+// speakerDisplayName は話者タグ (例: "[ずんだもん]") から表示用の話者名を取り出します。
+func speakerDisplayName(tag string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(tag, "["), "]")
+}
+
+// ----------------------------------------------------------------------
+// ファイル出力
+// ----------------------------------------------------------------------
+
+// writeOutputFiles は結合済みWAVを outputWavFile に書き出し、必要に応じてディレクトリを作成します。
+// formats が指定されている場合は、同じベース名で字幕ファイルも書き出します。
+func writeOutputFiles(outputWavFile string, result *Result, formats []subtitle.Format) error {
+	dir := filepath.Dir(outputWavFile)
+	if dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("出力ディレクトリの作成に失敗しました (%s): %w", dir, err)
+		}
+	}
+
+	if err := os.WriteFile(outputWavFile, result.WAV, 0644); err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(outputWavFile, filepath.Ext(outputWavFile))
+	for _, format := range formats {
+		var buf bytes.Buffer
+		if err := result.WriteSubtitles(&buf, format); err != nil {
+			return err
+		}
+
+		path := base + format.Extension()
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("字幕ファイルの書き込みに失敗しました (%s): %w", path, err)
+		}
+		slog.Info("字幕ファイルを出力しました。", "subtitle_file", path, "cues", len(result.Cues))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package voicevox
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// ----------------------------------------------------------------------
+// セグメント単位の再試行ポリシー
+// ----------------------------------------------------------------------
+
+// RetryPolicy は、失敗したセグメントの /audio_query と /synthesis の組を再試行する方針です。
+// httpkit による HTTP リクエスト単位の再試行とは別に、セグメント全体をやり直します。
+// MaxAttempts が 1 以下の場合は再試行しません。その他のゼロ値のフィールドには既定値 (DefaultRetryInitialBackoff など) が使用されます。
+type RetryPolicy struct {
+	// MaxAttempts は最初の試行を含む最大試行回数です。
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter は待機間隔に加える揺らぎの割合 (0〜1) です。同時に失敗したセグメントの再試行が集中するのを防ぎます。
+	Jitter float64
+}
+
+// enabled は再試行が有効であるかを返します。
+func (p RetryPolicy) enabled() bool {
+	return p.MaxAttempts > 1
+}
+
+// backoff は attempt 回目の試行が失敗した後に待機する時間を返します。
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial, maxBackoff, multiplier, jitter := p.InitialBackoff, p.MaxBackoff, p.Multiplier, p.Jitter
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	if multiplier < 1 {
+		multiplier = DefaultRetryMultiplier
+	}
+	if jitter <= 0 || jitter > 1 {
+		jitter = DefaultRetryJitter
+	}
+
+	delay := float64(initial) * math.Pow(multiplier, float64(attempt-1))
+	delay = math.Min(delay, float64(maxBackoff))
+	delay *= 1 + jitter*(2*rand.Float64()-1)
+	return time.Duration(delay)
+}
+
+// IsRetryable はセグメントの処理で発生したエラーが、再試行で回復する可能性があるかを判定します。
+//
+//   - 再試行する: タイムアウト、接続の拒否・切断、5xx、408、429 などの一時的な障害
+//   - 再試行しない: 422 などの入力の検証エラー、未定義のタグ (ErrUnknownStyleTag)、呼び出し元によるキャンセル
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// 呼び出し元によるキャンセルは再試行しない。試行ごとのタイムアウトは一時的な障害として扱う
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var tagErr *ErrUnknownStyleTag
+	if errors.As(err, &tagErr) {
+		return false
+	}
+
+	// エンジンが応答を返した場合はステータスコードで判定する (422 などの入力の誤りは再試行しない)
+	var respErr *api.ErrAPIResponse
+	if errors.As(err, &respErr) {
+		return isRetryableStatus(respErr.StatusCode)
+	}
+	var httpErr *httpkit.NonRetryableHTTPError
+	if errors.As(err, &httpErr) {
+		return isRetryableStatus(httpErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	// 応答を得られなかったその他の通信エラーは一時的な障害とみなす
+	var networkErr *api.ErrAPINetwork
+	return errors.As(err, &networkErr)
+}
+
+// isRetryableStatus は HTTP ステータスコードが一時的な障害を示すかを判定します。
+func isRetryableStatus(code int) bool {
+	return code >= http.StatusInternalServerError ||
+		code == http.StatusRequestTimeout ||
+		code == http.StatusTooManyRequests
+}
@@ -0,0 +1,175 @@
+package voicevox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "500", err: &api.ErrAPIResponse{StatusCode: 500}, want: true},
+		{name: "503 (ラップされたエラー)", err: fmt.Errorf("synthesis: %w", &api.ErrAPIResponse{StatusCode: 503}), want: true},
+		{name: "408", err: &api.ErrAPIResponse{StatusCode: 408}, want: true},
+		{name: "429", err: &api.ErrAPIResponse{StatusCode: 429}, want: true},
+		{name: "422", err: &api.ErrAPIResponse{StatusCode: 422}, want: false},
+		{name: "NonRetryableHTTPError (400)", err: &httpkit.NonRetryableHTTPError{StatusCode: 400}, want: false},
+		{name: "NonRetryableHTTPError (429)", err: &httpkit.NonRetryableHTTPError{StatusCode: 429}, want: true},
+		{name: "未定義のタグ", err: &ErrUnknownStyleTag{Tag: "[ずんだもん][あまあま]"}, want: false},
+		{name: "呼び出し元によるキャンセル", err: fmt.Errorf("audio_query: %w", context.Canceled), want: false},
+		{name: "試行のタイムアウト", err: fmt.Errorf("audio_query: %w", context.DeadlineExceeded), want: true},
+		{
+			name: "接続の拒否",
+			err:  &api.ErrAPINetwork{WrappedErr: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}},
+			want: true,
+		},
+		{name: "接続の拒否 (ErrAPINetwork 以外)", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: true},
+		{name: "その他の通信エラー", err: &api.ErrAPINetwork{WrappedErr: errors.New("unexpected EOF")}, want: true},
+		{name: "その他のエラー", err: errors.New("invalid WAV header"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRetryable(tt.err); got != tt.want {
+				t.Errorf("IsRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		want    time.Duration // 揺らぎを加える前の待機時間
+		jitter  float64
+	}{
+		{name: "既定値の1回目", attempt: 1, want: DefaultRetryInitialBackoff, jitter: DefaultRetryJitter},
+		{name: "既定値の2回目", attempt: 2, want: 2 * DefaultRetryInitialBackoff, jitter: DefaultRetryJitter},
+		{name: "既定値は上限で頭打ち", attempt: 10, want: DefaultRetryMaxBackoff, jitter: DefaultRetryJitter},
+		{
+			name:    "指定した倍率と上限",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 3, Jitter: 0.1},
+			attempt: 3, want: 900 * time.Millisecond, jitter: 0.1,
+		},
+		{
+			name:    "1未満の倍率は既定値を使用",
+			policy:  RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5, Jitter: 0.1},
+			attempt: 2, want: 200 * time.Millisecond, jitter: 0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			low := time.Duration(float64(tt.want) * (1 - tt.jitter))
+			high := time.Duration(float64(tt.want) * (1 + tt.jitter))
+			for range 20 {
+				if got := tt.policy.backoff(tt.attempt); got < low || got > high {
+					t.Fatalf("backoff(%d) = %v, want %v〜%v", tt.attempt, got, low, high)
+				}
+			}
+		})
+	}
+}
+
+// TestProcessSegmentRetry は再試行可能なエラーで /audio_query と /synthesis の組がやり直され、
+// MaxAttempts に達するか再試行できないエラーで停止することを確認します。
+func TestProcessSegmentRetry(t *testing.T) {
+	const text = "こんにちは"
+	unavailable := &api.ErrAPIResponse{StatusCode: 503}
+	invalid := &api.ErrAPIResponse{StatusCode: 422}
+
+	tests := []struct {
+		name         string
+		fail         func(text string, attempt int) error
+		wantCalls    int // /audio_query と /synthesis のそれぞれの呼び出し回数
+		wantAttempts int // 失敗した場合の SegmentError.Attempts (0 は成功)
+	}{
+		{
+			name: "一時的な障害は再試行で回復",
+			fail: func(text string, attempt int) error {
+				if attempt == 1 {
+					return unavailable
+				}
+				return nil
+			},
+			wantCalls: 2,
+		},
+		{
+			name:         "MaxAttempts で停止",
+			fail:         func(text string, attempt int) error { return unavailable },
+			wantCalls:    3,
+			wantAttempts: 3,
+		},
+		{
+			name:         "再試行できないエラーは1回で停止",
+			fail:         func(text string, attempt int) error { return invalid },
+			wantCalls:    1,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{fail: tt.fail}
+			e := newFakeEngine(client, EngineConfig{
+				Retry: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+			})
+			cfg := newExecuteConfig()
+			segments, _, err := e.prepareSegments(context.Background(), "[ずんだもん][ノーマル] "+text, cfg)
+			if err != nil || len(segments) != 1 {
+				t.Fatalf("prepareSegments = %d segments, %v", len(segments), err)
+			}
+
+			result := e.processSegment(context.Background(), segments[0], 0, cfg, nil)
+			if queries, syntheses := client.calls(text); queries != tt.wantCalls || syntheses != tt.wantCalls {
+				t.Errorf("呼び出し回数 = audio_query %d, synthesis %d; want それぞれ %d", queries, syntheses, tt.wantCalls)
+			}
+			if tt.wantAttempts == 0 {
+				if result.err != nil || result.wavData == nil {
+					t.Errorf("processSegment = %v, want 成功", result.err)
+				}
+				return
+			}
+			if result.err == nil || result.err.Attempts != tt.wantAttempts || result.err.Phase != PhaseSynthesis {
+				t.Errorf("processSegment error = %+v, want Phase %s, Attempts %d", result.err, PhaseSynthesis, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+// TestProcessSegmentCanceled はレートリミットの待機中のキャンセルが PhaseRateLimit のエラーとなり、
+// APIを呼び出さず、再試行もしないことを確認します。
+func TestProcessSegmentCanceled(t *testing.T) {
+	client := &fakeClient{}
+	e := newFakeEngine(client, EngineConfig{Retry: RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}})
+	cfg := newExecuteConfig()
+	segments, _, err := e.prepareSegments(context.Background(), "[ずんだもん][ノーマル] こんにちは", cfg)
+	if err != nil || len(segments) != 1 {
+		t.Fatalf("prepareSegments = %d segments, %v", len(segments), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := e.processSegment(ctx, segments[0], 0, cfg, nil)
+	if result.err == nil || result.err.Phase != PhaseRateLimit || result.err.Attempts != 1 || !errors.Is(result.err.Err, context.Canceled) {
+		t.Errorf("processSegment error = %+v, want Phase %s, Attempts 1, context.Canceled", result.err, PhaseRateLimit)
+	}
+	if queries, _ := client.calls("こんにちは"); queries != 0 {
+		t.Errorf("キャンセル後に /audio_query が %d 回呼び出されました", queries)
+	}
+}
@@ -0,0 +1,127 @@
+package rewrite
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ----------------------------------------------------------------------
+// 置換ルール
+// ----------------------------------------------------------------------
+
+// Rule は読み上げ用テキストに適用する1件の置換ルールです。
+// Regex が true の場合 Pattern は正規表現として扱われ、Replacement では $1 などのグループ参照を使用できます。
+type Rule struct {
+	Pattern     string `json:"pattern" yaml:"pattern"`
+	Replacement string `json:"replacement" yaml:"replacement"`
+	Regex       bool   `json:"regex,omitempty" yaml:"regex,omitempty"`
+	// Speakers は適用対象の話者タグ (例: "[ずんだもん]" または "ずんだもん") です。空の場合はすべての話者に適用されます。
+	// スクリプトに記述された話者タグと文字列として比較され、Style ID の解決で行う表記ゆれの吸収は適用されません。
+	// 話者の別名 (speaker.WithAliases) は解決されないため、別名で記述する話者にも適用する場合は別名も列挙してください。
+	Speakers []string `json:"speakers,omitempty" yaml:"speakers,omitempty"`
+}
+
+// compiledRule は検証・コンパイル済みのルールです。
+type compiledRule struct {
+	Rule
+	re       *regexp.Regexp
+	speakers map[string]bool
+}
+
+// RuleSet は順序付きの置換ルールの集合です。ルールは定義された順に適用されます。
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// New はルールを検証・コンパイルして RuleSet を作成します。
+func New(rules ...Rule) (*RuleSet, error) {
+	rs := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("ルール %d: pattern が空です", i+1)
+		}
+
+		compiled := compiledRule{Rule: rule}
+		if rule.Regex {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("ルール %d: 正規表現 %q のコンパイルに失敗しました: %w", i+1, rule.Pattern, err)
+			}
+			compiled.re = re
+		}
+		if len(rule.Speakers) > 0 {
+			compiled.speakers = make(map[string]bool, len(rule.Speakers))
+			for _, speaker := range rule.Speakers {
+				compiled.speakers[normalizeTag(speaker)] = true
+			}
+		}
+
+		rs.rules = append(rs.rules, compiled)
+	}
+	return rs, nil
+}
+
+// LoadFile はYAMLまたはJSONのルールファイルを読み込みます。
+// ファイルはルールの配列、または rules キーにルールの配列を持つオブジェクトです。
+func LoadFile(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("置換ルールファイルの読み込みに失敗しました (%s): %w", path, err)
+	}
+
+	var doc struct {
+		Rules []Rule `json:"rules" yaml:"rules"`
+	}
+	unmarshal := yaml.Unmarshal
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, &doc.Rules); err != nil {
+		if err := unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("置換ルールファイルの解析に失敗しました (%s): %w", path, err)
+		}
+	}
+
+	return New(doc.Rules...)
+}
+
+// Len はルールの件数を返します。
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// Apply は speakerTag の話者に該当するルールを順に適用したテキストを返します。
+// speakerTag はスクリプトに記述された話者タグ (スタイルタグを除く) です。
+func (rs *RuleSet) Apply(speakerTag, text string) string {
+	if rs == nil {
+		return text
+	}
+
+	tag := normalizeTag(speakerTag)
+	for _, rule := range rs.rules {
+		if rule.speakers != nil && !rule.speakers[tag] {
+			continue
+		}
+		if rule.re != nil {
+			text = rule.re.ReplaceAllString(text, rule.Replacement)
+		} else {
+			text = strings.ReplaceAll(text, rule.Pattern, rule.Replacement)
+		}
+	}
+	return text
+}
+
+// normalizeTag は話者名を角括弧付きのタグ形式に揃えます。
+func normalizeTag(name string) string {
+	name = strings.TrimSpace(name)
+	if strings.HasPrefix(name, "[") && strings.HasSuffix(name, "]") {
+		return name
+	}
+	return "[" + name + "]"
+}
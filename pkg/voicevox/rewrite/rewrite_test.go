@@ -0,0 +1,157 @@
+package rewrite
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		speaker string
+		in      string
+		want    string
+	}{
+		{
+			name:    "リテラルは正規表現として解釈しない",
+			rules:   []Rule{{Pattern: "v1.2", Replacement: "バージョン1.2"}},
+			speaker: "[ずんだもん]",
+			in:      "v1.2 と v102",
+			want:    "バージョン1.2 と v102",
+		},
+		{
+			name:    "正規表現とグループ参照",
+			rules:   []Rule{{Pattern: `(\d+)px`, Replacement: "${1}ピクセル", Regex: true}},
+			speaker: "[ずんだもん]",
+			in:      "幅は120pxです",
+			want:    "幅は120ピクセルです",
+		},
+		{
+			name: "後のルールは前のルールの置換結果にも適用",
+			rules: []Rule{
+				{Pattern: "k8s", Replacement: "Kubernetes"},
+				{Pattern: "Kubernetes", Replacement: "クバネティス"},
+			},
+			speaker: "[ずんだもん]",
+			in:      "k8sとKubernetes",
+			want:    "クバネティスとクバネティス",
+		},
+		{
+			name: "順序を入れ替えると結果が変わる",
+			rules: []Rule{
+				{Pattern: "Kubernetes", Replacement: "クバネティス"},
+				{Pattern: "k8s", Replacement: "Kubernetes"},
+			},
+			speaker: "[ずんだもん]",
+			in:      "k8sとKubernetes",
+			want:    "Kubernetesとクバネティス",
+		},
+		{
+			name: "話者ごとのルールは対象の話者にのみ適用",
+			rules: []Rule{
+				{Pattern: "僕", Replacement: "ボク", Speakers: []string{"ずんだもん"}},
+				{Pattern: "です", Replacement: "なのだ", Speakers: []string{"[ずんだもん]"}},
+				{Pattern: "API", Replacement: "エーピーアイ"},
+			},
+			speaker: "[めたん]",
+			in:      "僕のAPIです",
+			want:    "僕のエーピーアイです",
+		},
+		{
+			name: "話者名とタグのどちらでも指定できる",
+			rules: []Rule{
+				{Pattern: "僕", Replacement: "ボク", Speakers: []string{"ずんだもん"}},
+				{Pattern: "です", Replacement: "なのだ", Speakers: []string{"[ずんだもん]"}},
+				{Pattern: "API", Replacement: "エーピーアイ"},
+			},
+			speaker: "[ずんだもん]",
+			in:      "僕のAPIです",
+			want:    "ボクのエーピーアイなのだ",
+		},
+		{
+			name:    "話者タグは文字列として照合",
+			rules:   []Rule{{Pattern: "です", Replacement: "なのだ", Speakers: []string{"ずんだもん"}}},
+			speaker: "[ズンダモン]",
+			in:      "そうです",
+			want:    "そうです",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rs, err := New(tt.rules...)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			if got := rs.Apply(tt.speaker, tt.in); got != tt.want {
+				t.Errorf("Apply(%q, %q) = %q, want %q", tt.speaker, tt.in, got, tt.want)
+			}
+		})
+	}
+
+	var nilRules *RuleSet
+	if got := nilRules.Apply("[ずんだもん]", "そのまま"); got != "そのまま" {
+		t.Errorf("nil の RuleSet の Apply = %q, want そのまま", got)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantLen int
+		wantErr bool
+	}{
+		{
+			name:    "YAMLのルールの配列",
+			file:    "rules.yaml",
+			content: "- pattern: k8s\n  replacement: クバネティス\n- pattern: '(\\d+)px'\n  replacement: '${1}ピクセル'\n  regex: true\n",
+			wantLen: 2,
+		},
+		{
+			name:    "rules キーを持つYAML",
+			file:    "rules.yml",
+			content: "rules:\n  - pattern: 僕\n    replacement: ボク\n    speakers: [ずんだもん]\n",
+			wantLen: 1,
+		},
+		{
+			name:    "JSON",
+			file:    "rules.json",
+			content: `{"rules": [{"pattern": "API", "replacement": "エーピーアイ"}]}`,
+			wantLen: 1,
+		},
+		{name: "解析できない内容", file: "rules.json", content: `{"rules": [`, wantErr: true},
+		{name: "空の pattern", file: "rules.yaml", content: "- replacement: なし\n", wantErr: true},
+		{name: "不正な正規表現", file: "rules.yaml", content: "- pattern: '(未閉じ'\n  replacement: x\n  regex: true\n", wantErr: true},
+		{name: "存在しないファイル", file: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "missing.yaml")
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), tt.file)
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatalf("WriteFile: %v", err)
+				}
+			}
+
+			rs, err := LoadFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadFile = %d rules, want error", rs.Len())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadFile: %v", err)
+			}
+			if rs.Len() != tt.wantLen {
+				t.Errorf("Len = %d, want %d", rs.Len(), tt.wantLen)
+			}
+		})
+	}
+}
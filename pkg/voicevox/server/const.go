@@ -0,0 +1,18 @@
+package server
+
+import "time"
+
+// ----------------------------------------------------------------------
+// ジョブサービスの既定値
+// ----------------------------------------------------------------------
+
+const (
+	// DefaultWorkers は同時に実行するジョブの数です。各ジョブの中でセグメントは並列に合成されます。
+	DefaultWorkers = 1
+	// DefaultQueueSize は実行待ちにできるジョブの最大数です。超えた場合は 503 を返します。
+	DefaultQueueSize = 16
+	// DefaultJobRetention は完了したジョブ (と合成結果) をメモリに保持する期間です。
+	DefaultJobRetention = 1 * time.Hour
+	// MaxRequestBodyBytes はジョブ投入時のリクエストボディの最大サイズです。
+	MaxRequestBodyBytes = 1 << 20
+)
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// ----------------------------------------------------------------------
+// JSON エラーレスポンス
+// ----------------------------------------------------------------------
+
+// エラーコード
+// クライアントがメッセージの文言に依存せずにエラーを判別できるようにします。
+const (
+	CodeInvalidRequest    = "invalid_request"    // リクエストボディの形式が不正
+	CodeInvalidScript     = "invalid_script"     // スクリプトの解析に失敗した、または合成対象がない
+	CodeUnknownStyleTag   = "unknown_style_tag"  // 未定義の話者・スタイルタグ
+	CodeSynthesisFailed   = "synthesis_failed"   // セグメントの合成に失敗した
+	CodeInvalidInput      = "invalid_input"      // エンジンがテキストやカナを受け付けなかった (400, 422)
+	CodeEngineUnavailable = "engine_unavailable" // VOICEVOXエンジンに接続できない、またはエンジンの障害 (5xx)
+	CodeCanceled          = "canceled"           // ジョブがキャンセルされた
+	CodeQueueFull         = "queue_full"         // 実行待ちのジョブが上限に達している
+	CodeNotFound          = "not_found"          // ジョブが存在しない (または保持期間を過ぎた)
+	CodeConflict          = "conflict"           // ジョブの状態が操作と矛盾する
+	CodeInternal          = "internal"           // その他のエラー
+)
+
+// ErrorBody はエラーレスポンスの本体です。
+// セグメント単位のエラー (voicevox.SegmentError) は Segments に構造化して格納されます。
+type ErrorBody struct {
+	Code     string             `json:"code"`
+	Message  string             `json:"message"`
+	Segments []SegmentErrorBody `json:"segments,omitempty"`
+}
+
+// SegmentErrorBody は voicevox.SegmentError の JSON 表現です。
+type SegmentErrorBody struct {
+	Index       int                   `json:"index"`
+	SpeakerTag  string                `json:"speaker_tag"`
+	Text        string                `json:"text"`
+	Phase       voicevox.SegmentPhase `json:"phase"`
+	Message     string                `json:"message"`
+	Suggestions []string              `json:"suggestions,omitempty"`
+	// StatusCode と Detail は、エンジンがエラー応答を返した場合のステータスコードとその内容です。
+	StatusCode int              `json:"status_code,omitempty"`
+	Detail     *api.ErrorDetail `json:"detail,omitempty"`
+}
+
+// errorResponse はレスポンスのトップレベルの構造です。
+type errorResponse struct {
+	Error *ErrorBody `json:"error"`
+}
+
+// newErrorBody はエラーの種類からエラーコードを決定し、セグメント単位のエラーを展開します。
+func newErrorBody(err error) *ErrorBody {
+	body := &ErrorBody{Code: CodeInternal, Message: err.Error()}
+
+	var batchErr *voicevox.ErrSynthesisBatch
+	if errors.As(err, &batchErr) {
+		body.Code = CodeSynthesisFailed
+		for _, segErr := range batchErr.Errors {
+			segBody := SegmentErrorBody{
+				Index:      segErr.Index,
+				SpeakerTag: segErr.SpeakerTag,
+				Text:       segErr.Text,
+				Phase:      segErr.Phase,
+				Message:    segErr.Err.Error(),
+			}
+			var tagErr *voicevox.ErrUnknownStyleTag
+			if errors.As(segErr.Err, &tagErr) {
+				segBody.Suggestions = tagErr.Suggestions
+			}
+			var respErr *api.ErrAPIResponse
+			if errors.As(segErr.Err, &respErr) {
+				segBody.StatusCode = respErr.StatusCode
+				segBody.Detail = respErr.Detail
+			}
+			body.Segments = append(body.Segments, segBody)
+		}
+	}
+
+	var scriptErr *voicevox.ErrInvalidScript
+	var tagErr *voicevox.ErrUnknownStyleTag
+	var networkErr *api.ErrAPINetwork
+	var respErr *api.ErrAPIResponse
+	switch {
+	case errors.Is(err, context.Canceled):
+		body.Code = CodeCanceled
+	case errors.As(err, &scriptErr):
+		body.Code = CodeInvalidScript
+	case errors.As(err, &networkErr):
+		body.Code = CodeEngineUnavailable
+	case errors.As(err, &respErr) && respErr.IsServerError():
+		body.Code = CodeEngineUnavailable
+	case errors.As(err, &tagErr):
+		body.Code = CodeUnknownStyleTag
+	case respErr != nil && respErr.IsInputError():
+		body.Code = CodeInvalidInput
+	}
+	return body
+}
+
+// writeError はエラーを JSON で書き出します。
+func writeError(w http.ResponseWriter, status int, body *ErrorBody) {
+	writeJSON(w, status, errorResponse{Error: body})
+}
+
+// writeJSON は v を JSON で書き出します。
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("レスポンスの書き込みに失敗しました。", "error", err)
+	}
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/subtitle"
+)
+
+// ----------------------------------------------------------------------
+// HTTP ハンドラー
+// ----------------------------------------------------------------------
+
+// handleSubmit はスクリプトを受け付け、ジョブとしてキューに入れます。
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeSubmitRequest(w, r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, &ErrorBody{Code: CodeInvalidRequest, Message: err.Error()})
+		return
+	}
+
+	j, ok := s.enqueue(req)
+	if !ok {
+		w.Header().Set("Retry-After", "10")
+		writeError(w, http.StatusServiceUnavailable, &ErrorBody{
+			Code:    CodeQueueFull,
+			Message: fmt.Sprintf("実行待ちのジョブが上限 (%d件) に達しています", s.queueSize),
+		})
+		return
+	}
+
+	s.mu.Lock()
+	resp := j.response()
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/jobs/"+j.id)
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// decodeSubmitRequest はリクエストボディを SubmitRequest に変換します。
+func decodeSubmitRequest(w http.ResponseWriter, r *http.Request) (SubmitRequest, error) {
+	var req SubmitRequest
+	body := http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/plain" {
+		content, err := io.ReadAll(body)
+		if err != nil {
+			return req, fmt.Errorf("リクエストボディの読み込みに失敗しました: %w", err)
+		}
+		req.Script = string(content)
+	} else {
+		decoder := json.NewDecoder(body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return req, fmt.Errorf("リクエストボディのJSONが不正です: %w", err)
+		}
+	}
+
+	if strings.TrimSpace(req.Script) == "" {
+		return req, fmt.Errorf("script が空です")
+	}
+	return req, nil
+}
+
+// handleStatus はジョブの状態を返します。
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.lookupOrNotFound(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	resp := j.response()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// handleAudio は合成したWAVデータを返します。
+func (s *Server) handleAudio(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.lookupOrNotFound(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	status, result, jobErr := j.status, j.result, j.err
+	s.mu.Unlock()
+
+	if !status.hasAudio() {
+		writeNotReady(w, status, jobErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", "audio/wav")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", j.id+".wav"))
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(result.WAV)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(result.WAV)
+}
+
+// handleSubtitles は合成結果の字幕を返します。形式は format クエリパラメータ (srt / vtt) で指定します。
+func (s *Server) handleSubtitles(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.lookupOrNotFound(w, r)
+	if !ok {
+		return
+	}
+
+	format := subtitle.Format(r.URL.Query().Get("format"))
+	var contentType string
+	switch format {
+	case "", subtitle.FormatSRT:
+		format, contentType = subtitle.FormatSRT, "application/x-subrip; charset=utf-8"
+	case subtitle.FormatWebVTT:
+		contentType = "text/vtt; charset=utf-8"
+	default:
+		writeError(w, http.StatusBadRequest, &ErrorBody{
+			Code:    CodeInvalidRequest,
+			Message: fmt.Sprintf("未対応の字幕形式です: %s (srt, vtt のいずれかを指定してください)", format),
+		})
+		return
+	}
+
+	s.mu.Lock()
+	status, result, jobErr := j.status, j.result, j.err
+	s.mu.Unlock()
+
+	if !status.hasAudio() {
+		writeNotReady(w, status, jobErr)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", j.id+format.Extension()))
+	w.WriteHeader(http.StatusOK)
+	_ = result.WriteSubtitles(w, format)
+}
+
+// handleCancel はジョブをキャンセルします。
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	j, ok := s.lookupOrNotFound(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.cancelJob(j); err != nil {
+		writeError(w, http.StatusConflict, &ErrorBody{Code: CodeConflict, Message: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	resp := j.response()
+	s.mu.Unlock()
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// lookupOrNotFound はパスのジョブIDに対応するジョブを返します。見つからない場合は 404 を書き出します。
+func (s *Server) lookupOrNotFound(w http.ResponseWriter, r *http.Request) (*job, bool) {
+	id := r.PathValue("id")
+	j, ok := s.lookup(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, &ErrorBody{Code: CodeNotFound, Message: fmt.Sprintf("ジョブ %s が見つかりません", id)})
+	}
+	return j, ok
+}
+
+// writeNotReady は合成結果をダウンロードできない理由を 409 で書き出します。
+// 失敗・キャンセルしたジョブはその原因 (セグメント単位のエラーを含む) を返します。
+func writeNotReady(w http.ResponseWriter, status JobStatus, jobErr error) {
+	body := &ErrorBody{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("ジョブはまだ完了していません (status: %s)", status),
+	}
+	if status.finished() && jobErr != nil {
+		body = newErrorBody(jobErr)
+	}
+	writeError(w, http.StatusConflict, body)
+}
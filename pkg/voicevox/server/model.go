@@ -0,0 +1,95 @@
+package server
+
+import (
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+)
+
+// ----------------------------------------------------------------------
+// ジョブの状態
+// ----------------------------------------------------------------------
+
+// JobStatus はジョブの状態です。
+type JobStatus string
+
+const (
+	StatusQueued    JobStatus = "queued"    // 実行待ち
+	StatusRunning   JobStatus = "running"   // 合成中
+	StatusSucceeded JobStatus = "succeeded" // すべてのセグメントの合成に成功
+	StatusPartial   JobStatus = "partial"   // 一部のセグメントをスキップして出力 (partial 指定時)
+	StatusFailed    JobStatus = "failed"    // 合成に失敗
+	StatusCanceled  JobStatus = "canceled"  // キャンセルされた
+)
+
+// finished はジョブが終了状態であるかを返します。
+func (s JobStatus) finished() bool {
+	switch s {
+	case StatusSucceeded, StatusPartial, StatusFailed, StatusCanceled:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasAudio は合成結果の音声をダウンロードできる状態であるかを返します。
+func (s JobStatus) hasAudio() bool {
+	return s == StatusSucceeded || s == StatusPartial
+}
+
+// ----------------------------------------------------------------------
+// リクエストとレスポンス
+// ----------------------------------------------------------------------
+
+// SubmitRequest は POST /jobs のリクエストボディです。
+// Content-Type が text/plain の場合は、ボディ全体を Script として扱います。
+type SubmitRequest struct {
+	Script      string `json:"script"`
+	FallbackTag string `json:"fallback_tag,omitempty"`
+	// Partial が true の場合、一部のセグメントが失敗しても成功したセグメントで出力します。
+	Partial bool `json:"partial,omitempty"`
+	// FillSilence が true の場合、失敗したセグメントを推定長の無音で置き換えます (Partial 時のみ有効)。
+	FillSilence bool `json:"fill_silence,omitempty"`
+}
+
+// JobResponse はジョブの状態を表すレスポンスボディです。
+type JobResponse struct {
+	ID         string        `json:"id"`
+	Status     JobStatus     `json:"status"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	DurationMs int64         `json:"duration_ms,omitempty"`
+	Segments   []SegmentBody `json:"segments,omitempty"`
+	Error      *ErrorBody    `json:"error,omitempty"`
+}
+
+// SegmentBody は合成したセグメントのメタデータです (voicevox.SegmentInfo に対応)。
+type SegmentBody struct {
+	Index      int    `json:"index"`
+	SpeakerTag string `json:"speaker_tag,omitempty"`
+	Text       string `json:"text,omitempty"`
+	StyleID    int    `json:"style_id,omitempty"`
+	StartMs    int64  `json:"start_ms"`
+	EndMs      int64  `json:"end_ms"`
+	CacheHit   bool   `json:"cache_hit,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+}
+
+// newSegmentBodies は SegmentInfo をレスポンス用に変換します。
+func newSegmentBodies(infos []voicevox.SegmentInfo) []SegmentBody {
+	bodies := make([]SegmentBody, len(infos))
+	for i, info := range infos {
+		bodies[i] = SegmentBody{
+			Index:      info.Index,
+			SpeakerTag: info.SpeakerTag,
+			Text:       info.Text,
+			StyleID:    info.StyleID,
+			StartMs:    info.Start.Milliseconds(),
+			EndMs:      info.End.Milliseconds(),
+			CacheHit:   info.CacheHit,
+			Skipped:    info.Skipped,
+		}
+	}
+	return bodies
+}
@@ -0,0 +1,307 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+)
+
+// ----------------------------------------------------------------------
+// ジョブ
+// ----------------------------------------------------------------------
+
+// job は投入されたスクリプトの合成ジョブです。フィールドは Server.mu で保護されます。
+type job struct {
+	id         string
+	request    SubmitRequest
+	status     JobStatus
+	createdAt  time.Time
+	startedAt  time.Time
+	finishedAt time.Time
+	result     *voicevox.Result
+	err        error
+
+	// ctx はジョブごとのコンテキストです。キャンセルすると合成中のセグメントの処理も中断されます。
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// response はジョブの状態をレスポンス用に変換します。呼び出し元で Server.mu を保持している必要があります。
+func (j *job) response() JobResponse {
+	resp := JobResponse{
+		ID:        j.id,
+		Status:    j.status,
+		CreatedAt: j.createdAt,
+	}
+	if !j.startedAt.IsZero() {
+		startedAt := j.startedAt
+		resp.StartedAt = &startedAt
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		resp.FinishedAt = &finishedAt
+	}
+	if j.result != nil {
+		resp.DurationMs = j.result.Duration.Milliseconds()
+		resp.Segments = newSegmentBodies(j.result.Segments)
+	}
+	if j.err != nil {
+		resp.Error = newErrorBody(j.err)
+	}
+	return resp
+}
+
+// newJobID はランダムなジョブIDを生成します。
+func newJobID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b) // crypto/rand.Read はエラーを返さない
+	return hex.EncodeToString(b)
+}
+
+// ----------------------------------------------------------------------
+// サーバー
+// ----------------------------------------------------------------------
+
+// Server は Synthesizer を REST API のジョブサービスとして公開します。
+// 投入されたジョブは上限付きのキューに入り、ワーカーが順に Synthesize を実行します。
+type Server struct {
+	synthesizer    voicevox.Synthesizer
+	executeOptions []voicevox.ExecuteOption
+	workers        int
+	queueSize      int
+	retention      time.Duration
+
+	queue   chan *job
+	baseCtx context.Context
+
+	mu   sync.Mutex
+	jobs map[string]*job
+}
+
+// Option は Server の設定を行うための関数型です。
+type Option func(*Server)
+
+// WithWorkers は同時に実行するジョブの数を設定します。
+func WithWorkers(n int) Option {
+	return func(s *Server) {
+		s.workers = n
+	}
+}
+
+// WithQueueSize は実行待ちにできるジョブの最大数を設定します。
+func WithQueueSize(n int) Option {
+	return func(s *Server) {
+		s.queueSize = n
+	}
+}
+
+// WithJobRetention は完了したジョブと合成結果を保持する期間を設定します。
+func WithJobRetention(d time.Duration) Option {
+	return func(s *Server) {
+		s.retention = d
+	}
+}
+
+// WithExecuteOptions はすべてのジョブに適用する ExecuteOption (置換ルール、正規化など) を設定します。
+// ジョブごとの指定 (fallback_tag、partial) はこれより後に適用されます。
+func WithExecuteOptions(opts ...voicevox.ExecuteOption) Option {
+	return func(s *Server) {
+		s.executeOptions = append(s.executeOptions, opts...)
+	}
+}
+
+// New は新しい Server を作成します。ジョブを処理するには Start を呼び出す必要があります。
+func New(synthesizer voicevox.Synthesizer, opts ...Option) *Server {
+	s := &Server{
+		synthesizer: synthesizer,
+		workers:     DefaultWorkers,
+		queueSize:   DefaultQueueSize,
+		retention:   DefaultJobRetention,
+		baseCtx:     context.Background(),
+		jobs:        make(map[string]*job),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.workers <= 0 {
+		s.workers = DefaultWorkers
+	}
+	if s.queueSize <= 0 {
+		s.queueSize = DefaultQueueSize
+	}
+	s.queue = make(chan *job, s.queueSize)
+	return s
+}
+
+// Start はワーカーと、保持期間を過ぎたジョブを削除するGoルーチンを開始します。
+// ctx がキャンセルされると停止し、実行中のジョブもキャンセルされます。Handler を公開する前に呼び出してください。
+func (s *Server) Start(ctx context.Context) {
+	s.baseCtx = ctx
+	for i := 0; i < s.workers; i++ {
+		go s.worker(ctx)
+	}
+	go s.cleanup(ctx)
+	slog.InfoContext(ctx, "ジョブサービスを開始しました。", "workers", s.workers, "queue_size", s.queueSize, "retention", s.retention.String())
+}
+
+// Handler はジョブサービスの REST API を返します。
+//
+//	POST   /jobs                 スクリプトを投入する (202 Accepted)
+//	GET    /jobs/{id}            ジョブの状態を取得する
+//	GET    /jobs/{id}/audio      合成したWAVをダウンロードする
+//	GET    /jobs/{id}/subtitles  字幕をダウンロードする (?format=srt|vtt)
+//	DELETE /jobs/{id}            ジョブをキャンセルする
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /jobs", s.handleSubmit)
+	mux.HandleFunc("GET /jobs/{id}", s.handleStatus)
+	mux.HandleFunc("GET /jobs/{id}/audio", s.handleAudio)
+	mux.HandleFunc("GET /jobs/{id}/subtitles", s.handleSubtitles)
+	mux.HandleFunc("DELETE /jobs/{id}", s.handleCancel)
+	return mux
+}
+
+// ----------------------------------------------------------------------
+// ジョブの管理
+// ----------------------------------------------------------------------
+
+// enqueue はジョブを登録してキューに入れます。キューが満杯の場合は false を返します。
+func (s *Server) enqueue(req SubmitRequest) (*job, bool) {
+	ctx, cancel := context.WithCancel(s.baseCtx)
+	j := &job{
+		id:        newJobID(),
+		request:   req,
+		status:    StatusQueued,
+		createdAt: time.Now(),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case s.queue <- j:
+		s.jobs[j.id] = j
+		return j, true
+	default:
+		cancel()
+		return nil, false
+	}
+}
+
+// lookup はジョブIDに対応するジョブを返します。
+func (s *Server) lookup(id string) (*job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	return j, ok
+}
+
+// cancelJob はジョブをキャンセルします。実行待ちのジョブは即座に canceled になり、
+// 実行中のジョブはコンテキストのキャンセルにより合成が中断された時点で canceled になります。
+func (s *Server) cancelJob(j *job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case j.status == StatusQueued:
+		j.status = StatusCanceled
+		j.finishedAt = time.Now()
+		j.err = context.Canceled
+		j.cancel()
+	case j.status == StatusRunning:
+		j.cancel()
+	default:
+		return errors.New("終了したジョブはキャンセルできません")
+	}
+	return nil
+}
+
+// worker はキューからジョブを取り出して順に実行します。
+func (s *Server) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case j := <-s.queue:
+			s.run(j)
+		}
+	}
+}
+
+// run はジョブのスクリプトを合成し、結果をジョブに記録します。
+func (s *Server) run(j *job) {
+	s.mu.Lock()
+	if j.status != StatusQueued {
+		// キューで待機中にキャンセルされた
+		s.mu.Unlock()
+		return
+	}
+	j.status = StatusRunning
+	j.startedAt = time.Now()
+	s.mu.Unlock()
+
+	opts := append([]voicevox.ExecuteOption{}, s.executeOptions...)
+	opts = append(opts, voicevox.WithFallbackTag(j.request.FallbackTag))
+	if j.request.Partial {
+		opts = append(opts, voicevox.WithPartialOutput(j.request.FillSilence))
+	}
+
+	slog.InfoContext(j.ctx, "ジョブの実行を開始します。", "job_id", j.id, "script_length", len(j.request.Script))
+	result, err := s.synthesizer.Synthesize(j.ctx, j.request.Script, opts...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer j.cancel()
+
+	j.finishedAt = time.Now()
+	j.err = err
+
+	var partialErr *voicevox.ErrPartialSynthesis
+	switch {
+	case err == nil:
+		j.status = StatusSucceeded
+		j.result = result
+	case errors.As(err, &partialErr):
+		j.status = StatusPartial
+		j.result = result
+	case j.ctx.Err() != nil:
+		j.status = StatusCanceled
+	default:
+		j.status = StatusFailed
+	}
+	slog.InfoContext(j.ctx, "ジョブの実行が終了しました。", "job_id", j.id, "status", j.status, "elapsed", j.finishedAt.Sub(j.startedAt).String())
+}
+
+// cleanup は保持期間を過ぎた完了済みのジョブを定期的に削除します。
+func (s *Server) cleanup(ctx context.Context) {
+	if s.retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(min(s.retention, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for id, j := range s.jobs {
+				if j.status.finished() && now.Sub(j.finishedAt) > s.retention {
+					delete(s.jobs, id)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,402 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox"
+	"github.com/shouni/go-voicevox/pkg/voicevox/parser"
+)
+
+// ----------------------------------------------------------------------
+// テスト用のエンジン
+// ----------------------------------------------------------------------
+
+const testTag = "[ずんだもん][ノーマル]"
+
+// TestMain はログを破棄します。ログ出力のロックがゴルーチン間の同期として働き、
+// -race でデータ競合を検出できなくなるのを防ぐためです。
+func TestMain(m *testing.M) {
+	slog.SetDefault(slog.New(slog.DiscardHandler))
+	os.Exit(m.Run())
+}
+
+// fakeClient は固定のクエリと無音のWAVを返す AudioQueryClient です。
+// block が設定されている場合、/audio_query はチャネルが閉じられるかコンテキストが終了するまで待機します。
+type fakeClient struct {
+	block   chan struct{}
+	arrived chan string
+}
+
+func (c *fakeClient) RunAudioQuery(text string, styleID int, ctx context.Context) ([]byte, error) {
+	if c.arrived != nil {
+		c.arrived <- text
+	}
+	if c.block != nil {
+		select {
+		case <-c.block:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return []byte(`{"accent_phrases":[],"speedScale":1,"pitchScale":0,"intonationScale":1,"volumeScale":1,"prePhonemeLength":0.1,"postPhonemeLength":0.1,"outputSamplingRate":24000,"outputStereo":false}`), nil
+}
+
+func (c *fakeClient) RunSynthesis(queryBody []byte, styleID int, ctx context.Context) ([]byte, error) {
+	return testWAV(100 * time.Millisecond), nil
+}
+
+// fakeData は testTag のみを登録した DataFinder です。
+type fakeData struct{}
+
+func (fakeData) GetStyleID(tag string) (int, bool) {
+	return 3, tag == testTag
+}
+
+func (fakeData) GetDefaultTag(base string) (string, bool) {
+	if base == "[ずんだもん]" {
+		return testTag, true
+	}
+	return "", false
+}
+
+// testWAV は 24kHz・モノラル・16bit の無音のWAVを生成します。
+func testWAV(d time.Duration) []byte {
+	const sampleRate, blockAlign = 24000, 2
+	dataSize := uint32(int(d.Seconds()*sampleRate) * blockAlign)
+
+	var buf bytes.Buffer
+	buf.WriteString("RIFF")
+	_ = binary.Write(&buf, binary.LittleEndian, 36+dataSize)
+	buf.WriteString("WAVEfmt ")
+	for _, v := range []any{
+		uint32(16), uint16(1), uint16(1), uint32(sampleRate), uint32(sampleRate * blockAlign), uint16(blockAlign), uint16(16),
+	} {
+		_ = binary.Write(&buf, binary.LittleEndian, v)
+	}
+	buf.WriteString("data")
+	_ = binary.Write(&buf, binary.LittleEndian, dataSize)
+	buf.Write(make([]byte, dataSize))
+	return buf.Bytes()
+}
+
+// newTestServer は fakeClient を使用する Engine でジョブサービスを開始します。
+func newTestServer(t *testing.T, client *fakeClient, opts ...Option) (*Server, *httptest.Server) {
+	t.Helper()
+
+	engine := voicevox.NewEngine(client, fakeData{}, parser.NewParser(), voicevox.EngineConfig{
+		MaxParallelSegments: 2,
+		SegmentTimeout:      5 * time.Second,
+		SegmentRateLimit:    time.Millisecond,
+	})
+	s := New(engine, opts...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	s.Start(ctx)
+
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+// submit はスクリプトを投入し、ジョブIDを返します。
+func submit(t *testing.T, ts *httptest.Server, script string) string {
+	t.Helper()
+
+	body, _ := json.Marshal(SubmitRequest{Script: script})
+	resp, err := http.Post(ts.URL+"/jobs", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /jobs: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /jobs のステータスコード = %d, want %d", resp.StatusCode, http.StatusAccepted)
+	}
+	var job JobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatalf("レスポンスのデコード: %v", err)
+	}
+	return job.ID
+}
+
+// getJob はジョブの状態を取得します。
+func getJob(t *testing.T, ts *httptest.Server, id string) JobResponse {
+	t.Helper()
+
+	resp, err := http.Get(ts.URL + "/jobs/" + id)
+	if err != nil {
+		t.Fatalf("GET /jobs/%s: %v", id, err)
+	}
+	defer resp.Body.Close()
+
+	var job JobResponse
+	if err := json.NewDecoder(resp.Body).Decode(&job); err != nil {
+		t.Fatalf("レスポンスのデコード: %v", err)
+	}
+	return job
+}
+
+// waitFinished はジョブが終了状態になるまで待機します。
+func waitFinished(t *testing.T, ts *httptest.Server, id string) JobResponse {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job := getJob(t, ts, id)
+		if job.Status.finished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("ジョブ %s が終了しませんでした", id)
+	return JobResponse{}
+}
+
+// ----------------------------------------------------------------------
+// テスト
+// ----------------------------------------------------------------------
+
+// TestConcurrentJobs は複数のワーカーが同じ Engine で同時にジョブを実行しても、
+// 互いのセグメントが混ざらないことを確認します (go test -race で実行してください)。
+func TestConcurrentJobs(t *testing.T) {
+	client := &fakeClient{block: make(chan struct{}), arrived: make(chan string, 64)}
+	_, ts := newTestServer(t, client, WithWorkers(2))
+
+	scripts := map[string]string{
+		"first":  testTag + " 一つ目のジョブです。\n" + testTag + " 一つ目の二行目です。",
+		"second": testTag + " 二つ目のジョブです。\n" + testTag + " 二つ目の二行目です。",
+	}
+	ids := make(map[string]string)
+	for name, script := range scripts {
+		ids[name] = submit(t, ts, script)
+	}
+
+	// 両方のジョブが /audio_query に到達してから解放し、実行が重なっていることを保証する
+	seen := make(map[string]bool)
+	for len(seen) < 2 {
+		select {
+		case text := <-client.arrived:
+			seen[strings.SplitN(text, "つ目", 2)[0]] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("2つのジョブが同時に実行されませんでした (到達: %v)", seen)
+		}
+	}
+	close(client.block)
+
+	// 解析から合成までが重なるように、続けてジョブを投入する
+	for i := range 8 {
+		name := fmt.Sprintf("job%d", i)
+		scripts[name] = fmt.Sprintf("%s %d番目のジョブです。\n%s %d番目の二行目です。", testTag, i, testTag, i)
+		ids[name] = submit(t, ts, scripts[name])
+	}
+	go func() {
+		for range client.arrived {
+		}
+	}()
+
+	for name, id := range ids {
+		job := waitFinished(t, ts, id)
+		if job.Status != StatusSucceeded {
+			t.Fatalf("%s: status = %s, want %s (error: %+v)", name, job.Status, StatusSucceeded, job.Error)
+		}
+
+		var want, got []string
+		for _, line := range strings.Split(scripts[name], "\n") {
+			want = append(want, strings.TrimSpace(strings.TrimPrefix(line, testTag)))
+		}
+		for _, seg := range job.Segments {
+			got = append(got, seg.Text)
+		}
+		if strings.Join(got, "|") != strings.Join(want, "|") {
+			t.Errorf("%s: segments = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// request は ts にリクエストを送信し、ステータスコードとレスポンスボディを返します。
+func request(t *testing.T, ts *httptest.Server, method, path string, body io.Reader) (int, http.Header, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, ts.URL+path, body)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("%s %s のレスポンスの読み込み: %v", method, path, err)
+	}
+	return resp.StatusCode, resp.Header, data
+}
+
+// errorCode はエラーレスポンスのエラーコードを返します。
+func errorCode(t *testing.T, body []byte) string {
+	t.Helper()
+
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Error == nil {
+		t.Fatalf("エラーレスポンスではありません: %s", body)
+	}
+	return resp.Error.Code
+}
+
+// TestJobLifecycle は投入から完了までのジョブの状態と、完了後の音声・字幕のダウンロードを確認します。
+func TestJobLifecycle(t *testing.T) {
+	_, ts := newTestServer(t, &fakeClient{})
+
+	id := submit(t, ts, testTag+" こんにちは。\n"+testTag+" さようなら。")
+	job := waitFinished(t, ts, id)
+	if job.Status != StatusSucceeded || job.StartedAt == nil || job.FinishedAt == nil {
+		t.Fatalf("job = %+v, want succeeded with started_at and finished_at", job)
+	}
+	if len(job.Segments) != 2 || job.DurationMs <= 0 {
+		t.Errorf("segments = %d, duration_ms = %d; want 2 segments and positive duration", len(job.Segments), job.DurationMs)
+	}
+
+	tests := []struct {
+		name            string
+		method, path    string
+		wantStatus      int
+		wantContentType string
+		wantPrefix      string
+		wantCode        string
+	}{
+		{name: "音声のダウンロード", method: http.MethodGet, path: "/jobs/" + id + "/audio", wantStatus: http.StatusOK, wantContentType: "audio/wav", wantPrefix: "RIFF"},
+		{name: "SRT字幕 (既定)", method: http.MethodGet, path: "/jobs/" + id + "/subtitles", wantStatus: http.StatusOK, wantContentType: "application/x-subrip; charset=utf-8", wantPrefix: "1\n"},
+		{name: "WebVTT字幕", method: http.MethodGet, path: "/jobs/" + id + "/subtitles?format=vtt", wantStatus: http.StatusOK, wantContentType: "text/vtt; charset=utf-8", wantPrefix: "WEBVTT"},
+		{name: "未対応の字幕形式", method: http.MethodGet, path: "/jobs/" + id + "/subtitles?format=ass", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidRequest},
+		{name: "終了したジョブはキャンセルできない", method: http.MethodDelete, path: "/jobs/" + id, wantStatus: http.StatusConflict, wantCode: CodeConflict},
+		{name: "存在しないジョブ", method: http.MethodGet, path: "/jobs/unknown", wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "存在しないジョブの音声", method: http.MethodGet, path: "/jobs/unknown/audio", wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+		{name: "存在しないジョブのキャンセル", method: http.MethodDelete, path: "/jobs/unknown", wantStatus: http.StatusNotFound, wantCode: CodeNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, header, body := request(t, ts, tt.method, tt.path, nil)
+			if status != tt.wantStatus {
+				t.Fatalf("%s %s のステータスコード = %d, want %d (body: %s)", tt.method, tt.path, status, tt.wantStatus, body)
+			}
+			if tt.wantCode != "" {
+				if code := errorCode(t, body); code != tt.wantCode {
+					t.Errorf("エラーコード = %s, want %s", code, tt.wantCode)
+				}
+				return
+			}
+			if got := header.Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %s, want %s", got, tt.wantContentType)
+			}
+			if !bytes.HasPrefix(body, []byte(tt.wantPrefix)) {
+				t.Errorf("レスポンスボディが %q で始まりません: %.40q", tt.wantPrefix, body)
+			}
+		})
+	}
+}
+
+func TestSubmitInvalidRequest(t *testing.T) {
+	_, ts := newTestServer(t, &fakeClient{})
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "空のスクリプト", body: `{"script":"  "}`},
+		{name: "未知のフィールド", body: `{"script":"[ずんだもん] こんにちは","voice":"x"}`},
+		{name: "不正なJSON", body: `{"script":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _, body := request(t, ts, http.MethodPost, "/jobs", strings.NewReader(tt.body))
+			if status != http.StatusBadRequest {
+				t.Fatalf("ステータスコード = %d, want %d (body: %s)", status, http.StatusBadRequest, body)
+			}
+			if code := errorCode(t, body); code != CodeInvalidRequest {
+				t.Errorf("エラーコード = %s, want %s", code, CodeInvalidRequest)
+			}
+		})
+	}
+}
+
+// TestCancelJob は実行待ちのジョブと実行中のジョブのキャンセルを確認します。
+// ワーカーを1つにし、/audio_query で待機させることで、1件目を実行中、2件目を実行待ちの状態に固定します。
+func TestCancelJob(t *testing.T) {
+	client := &fakeClient{block: make(chan struct{}), arrived: make(chan string, 8)}
+	_, ts := newTestServer(t, client, WithWorkers(1))
+
+	running := submit(t, ts, testTag+" 実行中のジョブです。")
+	select {
+	case <-client.arrived:
+	case <-time.After(5 * time.Second):
+		t.Fatal("1件目のジョブが実行されませんでした")
+	}
+	queued := submit(t, ts, testTag+" 実行待ちのジョブです。")
+
+	if job := getJob(t, ts, running); job.Status != StatusRunning {
+		t.Fatalf("1件目の status = %s, want %s", job.Status, StatusRunning)
+	}
+	if job := getJob(t, ts, queued); job.Status != StatusQueued {
+		t.Fatalf("2件目の status = %s, want %s", job.Status, StatusQueued)
+	}
+	status, _, body := request(t, ts, http.MethodGet, "/jobs/"+running+"/audio", nil)
+	if status != http.StatusConflict || errorCode(t, body) != CodeConflict {
+		t.Fatalf("実行中のジョブの音声: status = %d, body = %s; want 409 conflict", status, body)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+	}{
+		{name: "実行待ちのジョブ", id: queued},
+		{name: "実行中のジョブ", id: running},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, _, body := request(t, ts, http.MethodDelete, "/jobs/"+tt.id, nil)
+			if status != http.StatusAccepted {
+				t.Fatalf("DELETE のステータスコード = %d, want %d (body: %s)", status, http.StatusAccepted, body)
+			}
+
+			job := waitFinished(t, ts, tt.id)
+			if job.Status != StatusCanceled {
+				t.Fatalf("status = %s, want %s", job.Status, StatusCanceled)
+			}
+			if job.Error == nil || job.Error.Code != CodeCanceled {
+				t.Errorf("error = %+v, want code %s", job.Error, CodeCanceled)
+			}
+
+			status, _, body = request(t, ts, http.MethodGet, "/jobs/"+tt.id+"/audio", nil)
+			if status != http.StatusConflict || errorCode(t, body) != CodeCanceled {
+				t.Errorf("キャンセルしたジョブの音声: status = %d, body = %s; want 409 %s", status, body, CodeCanceled)
+			}
+		})
+	}
+
+	// キャンセルしたジョブはワーカーを占有せず、後続のジョブが実行される
+	close(client.block)
+	next := submit(t, ts, testTag+" 後続のジョブです。")
+	if job := waitFinished(t, ts, next); job.Status != StatusSucceeded {
+		t.Errorf("後続のジョブの status = %s, want %s", job.Status, StatusSucceeded)
+	}
+}
@@ -0,0 +1,107 @@
+package speaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ----------------------------------------------------------------------
+// 話者の別名
+// ----------------------------------------------------------------------
+
+// Alias はスクリプトで使用する任意のタグを、VOICEVOXの話者 (API上の名前) に対応付けます。
+// 例: {Tag: "[ナレーター]", Speaker: "青山龍星", DefaultStyle: "ノーマル"}
+// 別名には話者のすべてのスタイルが登録され、"[ナレーター][しっとり]" のように指定できます。
+type Alias struct {
+	Tag          string `json:"tag" yaml:"tag"`
+	Speaker      string `json:"speaker" yaml:"speaker"`
+	DefaultStyle string `json:"default_style,omitempty" yaml:"default_style,omitempty"`
+}
+
+// WithAliases は話者の別名を登録するオプションです。
+// 同じタグが既に登録されている場合は、別名の設定で上書きされます。
+func WithAliases(aliases ...Alias) LoadOption {
+	return func(c *loadConfig) {
+		c.aliases = append(c.aliases, aliases...)
+	}
+}
+
+// LoadAliasFile はYAMLまたはJSONの別名設定ファイルを読み込みます。
+// ファイルは Alias の配列、または aliases キーに配列を持つオブジェクトです。
+func LoadAliasFile(path string) ([]Alias, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("話者の別名設定ファイルの読み込みに失敗しました (%s): %w", path, err)
+	}
+
+	var doc struct {
+		Aliases []Alias `json:"aliases" yaml:"aliases"`
+	}
+	unmarshal := yaml.Unmarshal
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		unmarshal = json.Unmarshal
+	}
+	if err := unmarshal(data, &doc.Aliases); err != nil {
+		if err := unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("話者の別名設定ファイルの解析に失敗しました (%s): %w", path, err)
+		}
+	}
+
+	for _, alias := range doc.Aliases {
+		if alias.Tag == "" || alias.Speaker == "" {
+			return nil, &ErrInvalidAlias{Tag: alias.Tag, Reason: "tag と speaker は必須です"}
+		}
+	}
+	return doc.Aliases, nil
+}
+
+// registerAlias は別名のタグに、対応する話者のすべてのスタイルとデフォルトスタイルを登録します。
+func registerAlias(data *SpeakerData, speakers []VVSpeaker, alias Alias) error {
+	tag := aliasTag(alias.Tag)
+
+	var target *VVSpeaker
+	for i := range speakers {
+		if speakers[i].Name == alias.Speaker {
+			target = &speakers[i]
+			break
+		}
+	}
+	if target == nil {
+		return &ErrInvalidAlias{Tag: tag, Reason: fmt.Sprintf("話者 %s がエンジンに見つかりません", alias.Speaker)}
+	}
+
+	// 既存のタグを再割り当てする場合は、以前の話者のスタイルを取り除く
+	for combinedTag := range data.StyleIDMap {
+		if strings.HasPrefix(combinedTag, tag+"[") {
+			delete(data.StyleIDMap, combinedTag)
+		}
+	}
+	delete(data.DefaultStyleMap, tag)
+	registerStyles(data, tag, *target, true)
+
+	if alias.DefaultStyle != "" {
+		defaultTag := tag + styleTagFor(alias.DefaultStyle)
+		if _, ok := data.StyleIDMap[defaultTag]; !ok {
+			return &ErrInvalidAlias{Tag: tag, Reason: fmt.Sprintf("話者 %s にスタイル %s がありません", alias.Speaker, alias.DefaultStyle)}
+		}
+		data.DefaultStyleMap[tag] = defaultTag
+	}
+
+	slog.Debug("話者の別名を登録しました", "alias", tag, "speaker", alias.Speaker, "default_style", data.DefaultStyleMap[tag])
+	return nil
+}
+
+// aliasTag は別名のタグを角括弧付きの形式に揃えます ("ずん" -> "[ずん]")。
+func aliasTag(tag string) string {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "[") && strings.HasSuffix(tag, "]") {
+		return tag
+	}
+	return DeriveTag(tag)
+}
@@ -0,0 +1,139 @@
+package speaker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadSpeakersAliases(t *testing.T) {
+	tests := []struct {
+		name        string
+		aliases     []Alias
+		wantStyles  map[string]int    // 登録されているべきタグ
+		wantAbsent  []string          // 登録されていてはならないタグ
+		wantDefault map[string]string // 話者タグ -> デフォルトスタイルのタグ
+	}{
+		{
+			name:        "別名に話者のすべてのスタイルを登録",
+			aliases:     []Alias{{Tag: "ナレーター", Speaker: "No.7"}},
+			wantStyles:  map[string]int{"[ナレーター][アナウンス]": 29, "[ナレーター][読み聞かせ]": 30},
+			wantDefault: map[string]string{"[ナレーター]": "[ナレーター][アナウンス]"},
+		},
+		{
+			name:        "デフォルトスタイルの指定",
+			aliases:     []Alias{{Tag: "[ずん]", Speaker: "ずんだもん", DefaultStyle: "ヒソヒソ"}},
+			wantStyles:  map[string]int{"[ずん][ノーマル]": 3, "[ずん][ささやき]": 22, "[ずん][ヒソヒソ]": 38},
+			wantDefault: map[string]string{"[ずん]": "[ずん][ヒソヒソ]"},
+		},
+		{
+			name:        "既存のタグの再割り当てで以前の話者のスタイルを取り除く",
+			aliases:     []Alias{{Tag: "[ずんだもん]", Speaker: "四国めたん"}},
+			wantStyles:  map[string]int{"[ずんだもん][ノーマル]": 2, "[ずんだもん][ツンツン]": 6, "[めたん][ノーマル]": 2},
+			wantAbsent:  []string{"[ずんだもん][ささやき]"},
+			wantDefault: map[string]string{"[ずんだもん]": "[ずんだもん][ノーマル]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: testSpeakersJSON}, WithAliases(tt.aliases...))
+			if err != nil {
+				t.Fatalf("LoadSpeakers: %v", err)
+			}
+			for tag, want := range tt.wantStyles {
+				if got, ok := data.GetStyleID(tag); !ok || got != want {
+					t.Errorf("GetStyleID(%q) = %d, %v; want %d", tag, got, ok, want)
+				}
+			}
+			for _, tag := range tt.wantAbsent {
+				if _, ok := data.GetStyleID(tag); ok {
+					t.Errorf("タグ %q が残っています", tag)
+				}
+			}
+			for tag, want := range tt.wantDefault {
+				if got, _ := data.GetDefaultTag(tag); got != want {
+					t.Errorf("GetDefaultTag(%q) = %q, want %q", tag, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadSpeakersInvalidAlias(t *testing.T) {
+	tests := []struct {
+		name  string
+		alias Alias
+	}{
+		{name: "存在しない話者", alias: Alias{Tag: "[ナレーター]", Speaker: "青山龍星"}},
+		{name: "存在しないデフォルトスタイル", alias: Alias{Tag: "[ずん]", Speaker: "ずんだもん", DefaultStyle: "あまあま"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: testSpeakersJSON}, WithAliases(tt.alias))
+			var invalid *ErrInvalidAlias
+			if !errors.As(err, &invalid) {
+				t.Fatalf("LoadSpeakers error = %v (%T), want *ErrInvalidAlias", err, err)
+			}
+		})
+	}
+}
+
+func TestLoadAliasFile(t *testing.T) {
+	want := []Alias{
+		{Tag: "[ナレーター]", Speaker: "No.7", DefaultStyle: "読み聞かせ"},
+		{Tag: "ずん", Speaker: "ずんだもん"},
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr bool
+	}{
+		{
+			name:    "YAMLの別名の配列",
+			file:    "aliases.yaml",
+			content: "- tag: '[ナレーター]'\n  speaker: No.7\n  default_style: 読み聞かせ\n- tag: ずん\n  speaker: ずんだもん\n",
+		},
+		{
+			name:    "aliases キーを持つYAML",
+			file:    "aliases.yml",
+			content: "aliases:\n  - tag: '[ナレーター]'\n    speaker: No.7\n    default_style: 読み聞かせ\n  - tag: ずん\n    speaker: ずんだもん\n",
+		},
+		{
+			name:    "JSON",
+			file:    "aliases.json",
+			content: `{"aliases": [{"tag": "[ナレーター]", "speaker": "No.7", "default_style": "読み聞かせ"}, {"tag": "ずん", "speaker": "ずんだもん"}]}`,
+		},
+		{name: "speaker がない", file: "aliases.yaml", content: "- tag: ずん\n", wantErr: true},
+		{name: "解析できない内容", file: "aliases.json", content: `[{"tag": `, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			got, err := LoadAliasFile(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("LoadAliasFile = %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadAliasFile: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("LoadAliasFile = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
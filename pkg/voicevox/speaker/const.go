@@ -5,6 +5,7 @@ package speaker
 // ----------------------------------------------------------------------
 
 // SupportedSpeakers は、このツールがサポートするすべて話者の一覧です。
+// WithAllSpeakers 指定時はホワイトリストではなく、API名から導出したタグに加えて登録される短縮タグ (別名) として扱われます。
 var SupportedSpeakers = []SpeakerMapping{
 	{APIName: "四国めたん", ToolTag: "[めたん]"},
 	{APIName: "ずんだもん", ToolTag: "[ずんだもん]"},
@@ -11,3 +11,22 @@ type ErrMissingRequiredField struct {
 func (e *ErrMissingRequiredField) Error() string {
 	return fmt.Sprintf("%sで必須フィールド '%s' が見つかりません", e.Context, e.Field)
 }
+
+// ErrInvalidAlias は話者の別名設定が不正であるか、エンジンの話者・スタイルと一致しないことを示します。
+type ErrInvalidAlias struct {
+	Tag    string
+	Reason string
+}
+
+func (e *ErrInvalidAlias) Error() string {
+	return fmt.Sprintf("話者の別名 %s の設定が不正です: %s", e.Tag, e.Reason)
+}
+
+// ErrUnsupportedSnapshot はスナップショットの形式のバージョンがサポートされていないことを示します。
+type ErrUnsupportedSnapshot struct {
+	Version int
+}
+
+func (e *ErrUnsupportedSnapshot) Error() string {
+	return fmt.Sprintf("サポートされていないスナップショットのバージョンです: %d (対応バージョン: %d)", e.Version, SnapshotVersion)
+}
@@ -0,0 +1,115 @@
+package speaker
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ----------------------------------------------------------------------
+// タグのあいまい照合
+// ----------------------------------------------------------------------
+
+// bracketReplacer は全角・和文の括弧を半角の角括弧に揃えます。
+var bracketReplacer = strings.NewReplacer(
+	"［", "[", "］", "]",
+	"【", "[", "】", "]",
+	"〔", "[", "〕", "]",
+	"「", "[", "」", "]",
+)
+
+// NormalizeTag はタグの表記ゆれを吸収した照合用の文字列を返します。
+// 全角/半角の括弧と英数字、空白、ひらがな/カタカナ、英字の大文字/小文字の違いを無視します。
+// 例: "【ずんだもん】 ［のーまる］" -> "[ズンダモン][ノーマル]"
+func NormalizeTag(tag string) string {
+	tag = bracketReplacer.Replace(tag)
+
+	var b strings.Builder
+	for _, r := range tag {
+		switch {
+		case unicode.IsSpace(r):
+			continue
+		case r >= '！' && r <= '～': // 全角ASCII -> 半角
+			r -= 0xFEE0
+		case r >= 'ぁ' && r <= 'ゖ': // ひらがな -> カタカナ
+			r += 'ァ' - 'ぁ'
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// FindTag は候補の中から、tag と表記ゆれを除いて一致するタグを返します。
+// 一致する候補が複数ある場合は曖昧なため見つからなかったものとして扱います。
+func FindTag(tag string, candidates []string) (string, bool) {
+	normalized := NormalizeTag(tag)
+	found := ""
+	for _, candidate := range candidates {
+		if NormalizeTag(candidate) != normalized {
+			continue
+		}
+		if found != "" {
+			return "", false
+		}
+		found = candidate
+	}
+	return found, found != ""
+}
+
+// SuggestTags は tag に近い候補を編集距離の近い順に最大 limit 件返します。
+// 距離がタグの長さの1/4 (最低1) を超える候補は含まれません。
+func SuggestTags(tag string, candidates []string, limit int) []string {
+	normalized := NormalizeTag(tag)
+	maxDistance := utf8.RuneCountInString(normalized) / 4
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	type scored struct {
+		tag      string
+		distance int
+	}
+	var matches []scored
+	for _, candidate := range candidates {
+		if d := editDistance(normalized, NormalizeTag(candidate)); d <= maxDistance {
+			matches = append(matches, scored{tag: candidate, distance: d})
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].tag < matches[j].tag
+	})
+
+	suggestions := make([]string, 0, limit)
+	for i := 0; i < len(matches) && i < limit; i++ {
+		suggestions = append(suggestions, matches[i].tag)
+	}
+	return suggestions
+}
+
+// editDistance は2つの文字列のレーベンシュタイン距離 (文字単位) を返します。
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,62 @@
+package speaker
+
+import (
+	"reflect"
+	"testing"
+)
+
+var testTags = []string{
+	"[ずんだもん][ノーマル]",
+	"[ずんだもん][ささやき]",
+	"[めたん][ノーマル]",
+	"[めたん][ツンツン]",
+}
+
+func TestFindTag(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        string
+		candidates []string
+		want       string
+		wantOK     bool
+	}{
+		{name: "完全一致", tag: "[ずんだもん][ノーマル]", candidates: testTags, want: "[ずんだもん][ノーマル]", wantOK: true},
+		{name: "全角括弧・空白・ひらがな", tag: "【ずんだもん】 ［のーまる］", candidates: testTags, want: "[ずんだもん][ノーマル]", wantOK: true},
+		{name: "カタカナの話者名", tag: "[メタン][つんつん]", candidates: testTags, want: "[めたん][ツンツン]", wantOK: true},
+		{name: "英字の大文字/小文字と全角英字", tag: "[ＺＵＮＤＡＭＯＮ]", candidates: []string{"[zundamon]"}, want: "[zundamon]", wantOK: true},
+		{name: "一致しない", tag: "[ずんだもん][あまあま]", candidates: testTags},
+		{name: "複数の候補に一致する場合は曖昧", tag: "[ずんだもん]", candidates: []string{"[ずんだもん]", "[ズンダモン]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := FindTag(tt.tag, tt.candidates)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("FindTag(%q) = %q, %v; want %q, %v", tt.tag, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSuggestTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tag        string
+		candidates []string
+		limit      int
+		want       []string
+	}{
+		{name: "スタイル名の1文字違い", tag: "[ずんだもん][ささやく]", candidates: testTags, limit: 3, want: []string{"[ずんだもん][ささやき]"}},
+		{name: "表記ゆれを除いた距離で比較", tag: "［めたん］［つんつそ］", candidates: testTags, limit: 3, want: []string{"[めたん][ツンツン]"}},
+		{name: "距離が同じ場合はタグ順で上限まで", tag: "[あ]", candidates: []string{"[え]", "[う]", "[い]"}, limit: 2, want: []string{"[い]", "[う]"}},
+		{name: "近い候補がない", tag: "[まったく別の話者]", candidates: testTags, limit: 3, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestTags(tt.tag, tt.candidates, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestTags(%q, limit=%d) = %q, want %q", tt.tag, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
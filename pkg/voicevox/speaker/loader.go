@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/shouni/go-voicevox/pkg/voicevox/api"
@@ -14,8 +15,36 @@ import (
 // ロードロジック
 // ----------------------------------------------------------------------
 
+// LoadOption は LoadSpeakers の動作を設定するオプションです。
+type LoadOption func(*loadConfig)
+
+type loadConfig struct {
+	allSpeakers bool
+	aliases     []Alias
+}
+
+// WithAllSpeakers は /speakers が返すすべての話者・スタイルを登録するオプションです。
+// 話者とスタイルのタグは API 上の名前から導出され (例: "[春日部つむぎ][ノーマル]")、
+// SupportedSpeakers の短縮タグ (例: "[めたん]") は別名として併せて登録されます。
+func WithAllSpeakers() LoadOption {
+	return func(c *loadConfig) {
+		c.allSpeakers = true
+	}
+}
+
+// DeriveTag は API 上の話者名・スタイル名からスクリプトで使用するタグを導出します (例: "ヒソヒソ" -> "[ヒソヒソ]")。
+func DeriveTag(name string) string {
+	return "[" + strings.Join(strings.Fields(name), "") + "]"
+}
+
 // LoadSpeakers は /speakers エンドポイントからデータを取得し、SpeakerDataを構築します。
-func LoadSpeakers(ctx context.Context, client SpeakerClient) (*SpeakerData, error) {
+// 既定では SupportedSpeakers と StyleApiNameToToolTag に定義された話者・スタイルのみを登録します。
+func LoadSpeakers(ctx context.Context, client SpeakerClient, opts ...LoadOption) (*SpeakerData, error) {
+	cfg := &loadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
 	// 1. 静的なSupportedSpeakersから、内部使用のためのマップを構築
 	apiNameToToolTag := make(map[string]string)
 	for _, mapping := range SupportedSpeakers {
@@ -42,32 +71,39 @@ func LoadSpeakers(ctx context.Context, client SpeakerClient) (*SpeakerData, erro
 
 	// 応答データから StyleIDMap と DefaultStyleMap を構築
 	for _, spk := range vvSpeakers {
-		toolTag, tagFound := apiNameToToolTag[spk.Name]
-		if !tagFound {
+		var speakerTags []string
+		if toolTag, tagFound := apiNameToToolTag[spk.Name]; tagFound {
+			speakerTags = append(speakerTags, toolTag)
+		}
+		if derived := DeriveTag(spk.Name); cfg.allSpeakers && !slices.Contains(speakerTags, derived) {
+			speakerTags = append(speakerTags, derived)
+		}
+		if len(speakerTags) == 0 {
 			continue // サポート対象外の話者はスキップ
 		}
 
-		for _, style := range spk.Styles {
-			styleTag, tagExists := StyleApiNameToToolTag[style.Name]
-			if !tagExists {
-				slog.Debug("サポートされていないスタイルをスキップします", "speaker", spk.Name, "style", style.Name)
-				continue
-			}
-
-			combinedTag := toolTag + styleTag
-			data.StyleIDMap[combinedTag] = style.ID
+		for _, toolTag := range speakerTags {
+			registerStyles(data, toolTag, spk, cfg.allSpeakers)
+		}
+	}
 
-			if styleTag == VvTagNormal {
-				data.DefaultStyleMap[toolTag] = combinedTag
-			}
+	// 別名の登録 (静的な短縮タグや導出したタグより優先)
+	for _, alias := range cfg.aliases {
+		if err := registerAlias(data, vvSpeakers, alias); err != nil {
+			return nil, err
 		}
 	}
 
 	// 5. 必須のデフォルトスタイルが存在するかチェック
+	// (すべての話者を登録する場合、SupportedSpeakers は別名にすぎないため必須としない)
 	missingDefaults := []string{}
 	for _, mapping := range SupportedSpeakers {
 		toolTag := mapping.ToolTag
 		if _, ok := data.DefaultStyleMap[toolTag]; !ok {
+			if cfg.allSpeakers {
+				slog.WarnContext(ctx, "別名を登録する話者がエンジンに見つかりません", "speaker", mapping.APIName, "alias", toolTag)
+				continue
+			}
 			slog.Error("必須話者のデフォルトスタイルが見つかりません", "speaker", toolTag, "required_style", VvTagNormal)
 			missingDefaults = append(missingDefaults, mapping.APIName)
 		}
@@ -84,3 +120,36 @@ func LoadSpeakers(ctx context.Context, client SpeakerClient) (*SpeakerData, erro
 
 	return data, nil
 }
+
+// registerStyles は話者 spk のスタイルを toolTag の下に登録します。
+// allStyles が false の場合は StyleApiNameToToolTag に定義されたスタイルのみを登録します。
+// デフォルトスタイルは "ノーマル" とし、存在しない場合 (allStyles 時のみ) は最初のスタイルを使用します。
+func registerStyles(data *SpeakerData, toolTag string, spk VVSpeaker, allStyles bool) {
+	for _, style := range spk.Styles {
+		if _, tagExists := StyleApiNameToToolTag[style.Name]; !tagExists && !allStyles {
+			slog.Debug("サポートされていないスタイルをスキップします", "speaker", spk.Name, "style", style.Name)
+			continue
+		}
+		styleTag := styleTagFor(style.Name)
+
+		combinedTag := toolTag + styleTag
+		data.StyleIDMap[combinedTag] = style.ID
+
+		if styleTag == VvTagNormal {
+			data.DefaultStyleMap[toolTag] = combinedTag
+		}
+	}
+
+	if _, ok := data.DefaultStyleMap[toolTag]; !ok && allStyles && len(spk.Styles) > 0 {
+		data.DefaultStyleMap[toolTag] = toolTag + styleTagFor(spk.Styles[0].Name)
+	}
+}
+
+// styleTagFor は API 上のスタイル名に対応するタグを返します。
+// StyleApiNameToToolTag に定義がない場合はスタイル名から導出します。
+func styleTagFor(styleName string) string {
+	if styleTag, ok := StyleApiNameToToolTag[styleName]; ok {
+		return styleTag
+	}
+	return DeriveTag(styleName)
+}
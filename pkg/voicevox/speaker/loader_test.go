@@ -0,0 +1,133 @@
+package speaker
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/shouni/go-voicevox/pkg/voicevox/api"
+)
+
+// testSpeakersJSON はテスト用の /speakers 応答です。
+// "No.7" にはノーマルのスタイルがなく、"冥鳴 ひまり" の名前には空白を含みます。
+const testSpeakersJSON = `[
+	{"name": "四国めたん", "styles": [{"name": "ノーマル", "id": 2}, {"name": "ツンツン", "id": 6}]},
+	{"name": "ずんだもん", "styles": [{"name": "ノーマル", "id": 3}, {"name": "ささやき", "id": 22}, {"name": "ヒソヒソ", "id": 38}]},
+	{"name": "冥鳴 ひまり", "styles": [{"name": "ノーマル", "id": 14}]},
+	{"name": "No.7", "styles": [{"name": "アナウンス", "id": 29}, {"name": "読み聞かせ", "id": 30}]}
+]`
+
+// fakeSpeakerClient は固定の応答を返す SpeakerClient です。
+type fakeSpeakerClient struct {
+	body string
+	err  error
+}
+
+func (c *fakeSpeakerClient) GetSpeakers(ctx context.Context) ([]byte, error) {
+	return []byte(c.body), c.err
+}
+
+func TestDeriveTag(t *testing.T) {
+	tests := map[string]string{
+		"ヒソヒソ":   "[ヒソヒソ]",
+		"冥鳴 ひまり": "[冥鳴ひまり]",
+		" No.7 ": "[No.7]",
+	}
+	for name, want := range tests {
+		if got := DeriveTag(name); got != want {
+			t.Errorf("DeriveTag(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLoadSpeakers(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []LoadOption
+		wantStyles  map[string]int
+		wantDefault map[string]string
+	}{
+		{
+			name: "対応する話者・スタイルのみ",
+			wantStyles: map[string]int{
+				"[めたん][ノーマル]": 2, "[めたん][ツンツン]": 6,
+				"[ずんだもん][ノーマル]": 3, "[ずんだもん][ささやき]": 22,
+			},
+			wantDefault: map[string]string{
+				"[めたん]":   "[めたん][ノーマル]",
+				"[ずんだもん]": "[ずんだもん][ノーマル]",
+			},
+		},
+		{
+			name: "すべての話者を導出したタグで登録し、短縮タグは別名として残す",
+			opts: []LoadOption{WithAllSpeakers()},
+			wantStyles: map[string]int{
+				"[四国めたん][ノーマル]": 2, "[四国めたん][ツンツン]": 6,
+				"[めたん][ノーマル]": 2, "[めたん][ツンツン]": 6,
+				"[ずんだもん][ノーマル]": 3, "[ずんだもん][ささやき]": 22, "[ずんだもん][ヒソヒソ]": 38,
+				"[冥鳴ひまり][ノーマル]": 14,
+				"[No.7][アナウンス]": 29, "[No.7][読み聞かせ]": 30,
+			},
+			wantDefault: map[string]string{
+				"[四国めたん]": "[四国めたん][ノーマル]",
+				"[めたん]":   "[めたん][ノーマル]",
+				"[ずんだもん]": "[ずんだもん][ノーマル]",
+				"[冥鳴ひまり]": "[冥鳴ひまり][ノーマル]",
+				"[No.7]":  "[No.7][アナウンス]", // ノーマルがない場合は最初のスタイル
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: testSpeakersJSON}, tt.opts...)
+			if err != nil {
+				t.Fatalf("LoadSpeakers: %v", err)
+			}
+			if !reflect.DeepEqual(data.StyleIDMap, tt.wantStyles) {
+				t.Errorf("StyleIDMap = %v, want %v", data.StyleIDMap, tt.wantStyles)
+			}
+			if !reflect.DeepEqual(data.DefaultStyleMap, tt.wantDefault) {
+				t.Errorf("DefaultStyleMap = %v, want %v", data.DefaultStyleMap, tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestLoadSpeakersErrors(t *testing.T) {
+	const withoutMetan = `[{"name": "ずんだもん", "styles": [{"name": "ノーマル", "id": 3}]}]`
+
+	t.Run("必須話者がない", func(t *testing.T) {
+		_, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: withoutMetan})
+		var missing *ErrMissingRequiredField
+		if !errors.As(err, &missing) {
+			t.Fatalf("LoadSpeakers error = %v (%T), want *ErrMissingRequiredField", err, err)
+		}
+	})
+
+	t.Run("すべての話者を登録する場合は必須としない", func(t *testing.T) {
+		data, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: withoutMetan}, WithAllSpeakers())
+		if err != nil {
+			t.Fatalf("LoadSpeakers: %v", err)
+		}
+		if _, ok := data.GetDefaultTag("[めたん]"); ok {
+			t.Error("エンジンにない話者 [めたん] のデフォルトスタイルが登録されました")
+		}
+	})
+
+	t.Run("不正な応答JSON", func(t *testing.T) {
+		_, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: `{"name": "ずんだもん"}`})
+		var invalid *api.ErrInvalidJSON
+		if !errors.As(err, &invalid) {
+			t.Fatalf("LoadSpeakers error = %v (%T), want *api.ErrInvalidJSON", err, err)
+		}
+	})
+
+	t.Run("APIのエラー", func(t *testing.T) {
+		wantErr := errors.New("connection refused")
+		if _, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{err: wantErr}); !errors.Is(err, wantErr) {
+			t.Fatalf("LoadSpeakers error = %v, want %v", err, wantErr)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package speaker
 
-import "context"
+import (
+	"context"
+	"sort"
+)
 
 // ----------------------------------------------------------------------
 // インターフェース定義
@@ -51,6 +54,16 @@ func (d *SpeakerData) GetStyleID(tag string) (styleID int, ok bool) {
 	return id, found
 }
 
+// Tags は登録されているすべての話者・スタイルタグを辞書順で返します (あいまい照合の候補に使用します)。
+func (d *SpeakerData) Tags() []string {
+	tags := make([]string, 0, len(d.StyleIDMap))
+	for tag := range d.StyleIDMap {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
 // BaseSpeakerTag からデフォルトスタイルタグを検索します (DataFinder 実装)
 func (d *SpeakerData) GetDefaultTag(baseSpeakerTag string) (fallbackKey string, ok bool) {
 	key, found := d.DefaultStyleMap[baseSpeakerTag]
@@ -0,0 +1,117 @@
+package speaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// オフライン用スナップショット
+// ----------------------------------------------------------------------
+
+// SnapshotVersion は現在のスナップショット形式のバージョンです。
+// 形式を変更した場合はこの値を上げ、古いバージョンの読み込みを ReadSnapshot で扱います。
+const SnapshotVersion = 1
+
+// Snapshot は SpeakerData をエンジンなしで復元するための、バージョン付きのJSON表現です。
+type Snapshot struct {
+	Version       int               `json:"version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	EngineVersion string            `json:"engine_version,omitempty"`
+	StyleIDMap    map[string]int    `json:"style_ids"`
+	DefaultStyles map[string]string `json:"default_styles"`
+}
+
+// Snapshot は現在の話者データのスナップショットを作成します。
+// engineVersion には取得元のエンジンのバージョンを指定します (不明な場合は空文字列)。
+func (d *SpeakerData) Snapshot(engineVersion string) *Snapshot {
+	snapshot := &Snapshot{
+		Version:       SnapshotVersion,
+		CreatedAt:     time.Now().UTC(),
+		EngineVersion: engineVersion,
+		StyleIDMap:    make(map[string]int, len(d.StyleIDMap)),
+		DefaultStyles: make(map[string]string, len(d.DefaultStyleMap)),
+	}
+	for tag, id := range d.StyleIDMap {
+		snapshot.StyleIDMap[tag] = id
+	}
+	for tag, defaultTag := range d.DefaultStyleMap {
+		snapshot.DefaultStyles[tag] = defaultTag
+	}
+	return snapshot
+}
+
+// SpeakerData はスナップショットから話者データを復元します。
+func (s *Snapshot) SpeakerData() *SpeakerData {
+	data := &SpeakerData{
+		StyleIDMap:      make(map[string]int, len(s.StyleIDMap)),
+		DefaultStyleMap: make(map[string]string, len(s.DefaultStyles)),
+	}
+	for tag, id := range s.StyleIDMap {
+		data.StyleIDMap[tag] = id
+	}
+	for tag, defaultTag := range s.DefaultStyles {
+		data.DefaultStyleMap[tag] = defaultTag
+	}
+	return data
+}
+
+// WriteSnapshot はスナップショットをJSONとして w に書き込みます。
+func WriteSnapshot(w io.Writer, snapshot *Snapshot) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(snapshot)
+}
+
+// ReadSnapshot はJSONのスナップショットを読み込み、バージョンを検証します。
+func ReadSnapshot(r io.Reader) (*Snapshot, error) {
+	var snapshot Snapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("話者データのスナップショットのデコードに失敗しました: %w", err)
+	}
+	if snapshot.Version != SnapshotVersion {
+		return nil, &ErrUnsupportedSnapshot{Version: snapshot.Version}
+	}
+	if len(snapshot.StyleIDMap) == 0 {
+		return nil, &ErrMissingRequiredField{Field: "style_ids", Context: "話者データのスナップショット読み込み時"}
+	}
+	return &snapshot, nil
+}
+
+// SaveSnapshot はスナップショットをファイルに保存します。一時ファイルへの書き込み後に置き換えるため、
+// 書き込み中に読み込まれても不完全なファイルは参照されません。
+func SaveSnapshot(path string, snapshot *Snapshot) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("スナップショットの保存先ディレクトリの作成に失敗しました (%s): %w", dir, err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".speakers-*.json")
+	if err != nil {
+		return fmt.Errorf("スナップショットの一時ファイルの作成に失敗しました: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteSnapshot(tmp, snapshot); err != nil {
+		tmp.Close()
+		return fmt.Errorf("スナップショットの書き込みに失敗しました: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// LoadSnapshot はファイルからスナップショットを読み込みます。
+func LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadSnapshot(f)
+}
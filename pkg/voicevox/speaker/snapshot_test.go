@@ -0,0 +1,78 @@
+package speaker
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	data, err := LoadSpeakers(context.Background(), &fakeSpeakerClient{body: testSpeakersJSON},
+		WithAllSpeakers(), WithAliases(Alias{Tag: "[ずん]", Speaker: "ずんだもん", DefaultStyle: "ささやき"}))
+	if err != nil {
+		t.Fatalf("LoadSpeakers: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "cache")
+	path := filepath.Join(dir, "speakers.json")
+	if err := SaveSnapshot(path, data.Snapshot("0.21.1")); err != nil {
+		t.Fatalf("SaveSnapshot: %v", err)
+	}
+
+	snapshot, err := LoadSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshot: %v", err)
+	}
+	if snapshot.Version != SnapshotVersion || snapshot.EngineVersion != "0.21.1" || snapshot.CreatedAt.IsZero() {
+		t.Errorf("Snapshot = {Version: %d, EngineVersion: %q, CreatedAt: %v}, want バージョン %d と 0.21.1", snapshot.Version, snapshot.EngineVersion, snapshot.CreatedAt, SnapshotVersion)
+	}
+	if restored := snapshot.SpeakerData(); !reflect.DeepEqual(restored, data) {
+		t.Errorf("復元した話者データ = %+v, want %+v", restored, data)
+	}
+
+	// 一時ファイルは置き換え後に残らない
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("保存先のファイル数 = %d, want 1 (%v)", len(entries), entries)
+	}
+}
+
+func TestReadSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantVersion int // ErrUnsupportedSnapshot を期待する場合のバージョン
+	}{
+		{name: "新しいバージョン", content: `{"version": 2, "style_ids": {"[ずんだもん][ノーマル]": 3}}`, wantVersion: 2},
+		{name: "バージョンなし", content: `{"style_ids": {"[ずんだもん][ノーマル]": 3}}`, wantVersion: 0},
+		{name: "style_ids が空", content: `{"version": 1, "style_ids": {}}`, wantVersion: -1},
+		{name: "解析できない内容", content: `{"version": `, wantVersion: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := ReadSnapshot(strings.NewReader(tt.content))
+			if err == nil {
+				t.Fatalf("ReadSnapshot = %+v, want error", snapshot)
+			}
+			var unsupported *ErrUnsupportedSnapshot
+			if isUnsupported := errors.As(err, &unsupported); isUnsupported != (tt.wantVersion >= 0) {
+				t.Fatalf("ReadSnapshot error = %v (%T)", err, err)
+			}
+			if unsupported != nil && unsupported.Version != tt.wantVersion {
+				t.Errorf("ErrUnsupportedSnapshot.Version = %d, want %d", unsupported.Version, tt.wantVersion)
+			}
+		})
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadSnapshot error = %v, want os.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,103 @@
+package subtitle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ----------------------------------------------------------------------
+// データモデル
+// ----------------------------------------------------------------------
+
+// Format は字幕ファイルの形式です。
+type Format string
+
+const (
+	FormatSRT    Format = "srt"
+	FormatWebVTT Format = "vtt"
+)
+
+// Extension は字幕ファイルの拡張子 (ドットを含む) を返します。
+func (f Format) Extension() string {
+	return "." + string(f)
+}
+
+// Cue は字幕の1表示単位です。一つの音声セグメントに対応します。
+type Cue struct {
+	Start   time.Duration
+	End     time.Duration
+	Speaker string // 例: "ずんだもん"
+	Text    string
+}
+
+// ----------------------------------------------------------------------
+// 書き出し
+// ----------------------------------------------------------------------
+
+// Write は指定された形式で字幕を書き出します。
+func Write(w io.Writer, format Format, cues []Cue) error {
+	switch format {
+	case FormatSRT:
+		return WriteSRT(w, cues)
+	case FormatWebVTT:
+		return WriteWebVTT(w, cues)
+	default:
+		return fmt.Errorf("未対応の字幕形式です: %s", format)
+	}
+}
+
+// WriteSRT は SubRip (SRT) 形式で字幕を書き出します。話者名はテキストの先頭に "話者: " として付与されます。
+func WriteSRT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	for i, cue := range cues {
+		text := cueText(cue.Text)
+		if cue.Speaker != "" {
+			text = cue.Speaker + ": " + text
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n",
+			i+1, formatTimestamp(cue.Start, ','), formatTimestamp(cue.End, ','), text)
+	}
+	return bw.Flush()
+}
+
+// WriteWebVTT は WebVTT 形式で字幕を書き出します。話者名は voice タグ (<v 話者>) で表現されます。
+func WriteWebVTT(w io.Writer, cues []Cue) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("WEBVTT\n\n")
+	for i, cue := range cues {
+		text := escapeVTT(cueText(cue.Text))
+		if cue.Speaker != "" {
+			text = fmt.Sprintf("<v %s>%s", escapeVTT(cue.Speaker), text)
+		}
+		fmt.Fprintf(bw, "%d\n%s --> %s\n%s\n\n",
+			i+1, formatTimestamp(cue.Start, '.'), formatTimestamp(cue.End, '.'), text)
+	}
+	return bw.Flush()
+}
+
+// ----------------------------------------------------------------------
+// 内部ヘルパー
+// ----------------------------------------------------------------------
+
+// formatTimestamp は HH:MM:SS,mmm (SRT) または HH:MM:SS.mmm (WebVTT) 形式の時刻を返します。
+func formatTimestamp(d time.Duration, fractionSep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d",
+		ms/3_600_000, ms/60_000%60, ms/1000%60, fractionSep, ms%1000)
+}
+
+// cueText は字幕の区切りとして解釈される空行を含まないよう、テキストを1行にまとめます。
+func cueText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// escapeVTT は WebVTT で特別な意味を持つ文字をエスケープします。
+func escapeVTT(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
@@ -0,0 +1,88 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var testCues = []Cue{
+	{Start: 0, End: 1500 * time.Millisecond, Speaker: "ずんだもん", Text: "こんにちは\n\nなのだ"},
+	{Start: 59*time.Minute + 59*time.Second + 999*time.Millisecond, End: time.Hour + 2*time.Second + 5*time.Millisecond, Speaker: "めたん", Text: "A&B <i>タグ</i> 1 > 0"},
+	{Start: 10*time.Hour + 30*time.Second, End: 10*time.Hour + 31*time.Second, Text: "話者なし"},
+}
+
+func TestWriteSRT(t *testing.T) {
+	const want = `1
+00:00:00,000 --> 00:00:01,500
+ずんだもん: こんにちは なのだ
+
+2
+00:59:59,999 --> 01:00:02,005
+めたん: A&B <i>タグ</i> 1 > 0
+
+3
+10:00:30,000 --> 10:00:31,000
+話者なし
+
+`
+	var b strings.Builder
+	if err := Write(&b, FormatSRT, testCues); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("WriteSRT =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteWebVTT(t *testing.T) {
+	const want = `WEBVTT
+
+1
+00:00:00.000 --> 00:00:01.500
+<v ずんだもん>こんにちは なのだ
+
+2
+00:59:59.999 --> 01:00:02.005
+<v めたん>A&amp;B &lt;i&gt;タグ&lt;/i&gt; 1 &gt; 0
+
+3
+10:00:30.000 --> 10:00:31.000
+話者なし
+
+`
+	var b strings.Builder
+	if err := Write(&b, FormatWebVTT, testCues); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("WriteWebVTT =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "00:00:00,000"},
+		{d: -time.Second, want: "00:00:00,000"},
+		{d: 999*time.Millisecond + 999*time.Microsecond, want: "00:00:00,999"},
+		{d: 59*time.Minute + 59*time.Second + 999*time.Millisecond, want: "00:59:59,999"},
+		{d: time.Hour, want: "01:00:00,000"},
+		{d: 100*time.Hour + time.Minute + time.Second + time.Millisecond, want: "100:01:01,001"},
+	}
+
+	for _, tt := range tests {
+		if got := formatTimestamp(tt.d, ','); got != tt.want {
+			t.Errorf("formatTimestamp(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var b strings.Builder
+	if err := Write(&b, Format("ass"), testCues); err == nil {
+		t.Error("未対応の字幕形式で Write が成功しました, want error")
+	}
+}